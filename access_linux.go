@@ -0,0 +1,182 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"syscall"
+)
+
+// flags got in: `man 2 faccessat`
+const (
+	atFDCWD   = -100  // use the current working directory
+	atEACCESS = 0x200 // use the effective ids
+)
+
+// Tags of the entries of a POSIX ACL, got in: 'include/uapi/linux/posix_acl.h'
+const (
+	aclUserObj  = 0x01
+	aclUser     = 0x02
+	aclGroupObj = 0x04
+	aclGroup    = 0x08
+	aclMask     = 0x10
+	aclOther    = 0x20
+)
+
+// can reports whether the process has the permission p on the file, using its
+// effective user and group ids, its supplementary groups and the POSIX ACL.
+func (i Info) can(p Perm) bool {
+	groups := map[uint32]bool{uint32(os.Getegid()): true}
+	if gids, err := os.Getgroups(); err == nil {
+		for _, g := range gids {
+			groups[uint32(g)] = true
+		}
+	}
+	return i.canAs(uint32(os.Geteuid()), groups, p)
+}
+
+// canAs reports whether the user uid, member of groups, has the permission p
+// on the file.
+func (i Info) canAs(uid uint32, groups map[uint32]bool, p Perm) bool {
+	st, ok := i.fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	want := permBits(p)
+	mode := uint32(i.fi.Mode().Perm())
+
+	// The superuser can read and write anything, and execute files with some
+	// execute bit.
+	if uid == 0 {
+		return want != 1 || i.fi.IsDir() || mode&0111 != 0
+	}
+
+	acl, _ := i.acl()
+	if acl == nil {
+		switch {
+		case uid == st.Uid:
+			return mode>>6&want == want
+		case groups[st.Gid]:
+			return mode>>3&want == want
+		default:
+			return mode&want == want
+		}
+	}
+
+	// Access check algorithm: `man 5 acl`
+	if uid == st.Uid {
+		return acl[aclUserObj]&want == want
+	}
+
+	mask := uint32(07)
+	if m, ok := acl[aclMask]; ok {
+		mask = m
+	}
+	if bits, ok := acl.user(uid); ok {
+		return bits&mask&want == want
+	}
+
+	inGroup := false
+	if groups[st.Gid] {
+		inGroup = true
+		if acl[aclGroupObj]&mask&want == want {
+			return true
+		}
+	}
+	for gid := range groups {
+		if bits, ok := acl.group(gid); ok {
+			inGroup = true
+			if bits&mask&want == want {
+				return true
+			}
+		}
+	}
+	if inGroup {
+		return false
+	}
+	return acl[aclOther]&want == want
+}
+
+// posixACL represents the entries of a POSIX ACL. The entries of owner, group,
+// mask and others are indexed by their tag; the entries of users and groups
+// are indexed by tag<<32 | id.
+type posixACL map[uint64]uint32
+
+func (a posixACL) user(uid uint32) (uint32, bool) {
+	bits, ok := a[aclUser<<32|uint64(uid)]
+	return bits, ok
+}
+
+func (a posixACL) group(gid uint32) (uint32, bool) {
+	bits, ok := a[aclGroup<<32|uint64(gid)]
+	return bits, ok
+}
+
+// acl returns the access ACL of the file, or nil if it has not.
+func (i Info) acl() (posixACL, error) {
+	b, err := GetXattr(i.name, xattrACLAccess)
+	if err != nil {
+		if isNotSupported(err) || errors.Is(err, syscall.ENODATA) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, nil
+	}
+
+	// Format at extended attribute: version (4 bytes), and then the entries of
+	// tag (2 bytes), permissions (2 bytes) and id (4 bytes), in little endian.
+	if binary.LittleEndian.Uint32(b) != 2 {
+		return nil, nil
+	}
+	acl := make(posixACL)
+
+	for b = b[4:]; len(b) >= 8; b = b[8:] {
+		tag := uint64(binary.LittleEndian.Uint16(b))
+		bits := uint32(binary.LittleEndian.Uint16(b[2:]))
+		id := uint64(binary.LittleEndian.Uint32(b[4:]))
+
+		if tag == aclUser || tag == aclGroup {
+			acl[tag<<32|id] = bits
+		} else {
+			acl[tag] = bits
+		}
+	}
+	return acl, nil
+}
+
+// canAccess reports whether the process has the permission p on the named
+// file, using the system call faccessat with the effective ids.
+func canAccess(name string, p Perm) (bool, error) {
+	err := syscall.Faccessat(atFDCWD, name, permBits(p), atEACCESS)
+	if err == nil {
+		return true, nil
+	}
+	if err == syscall.EACCES || err == syscall.EROFS || err == syscall.ETXTBSY {
+		return false, nil
+	}
+	return false, &os.PathError{Op: "faccessat", Path: name, Err: err}
+}
+
+// permBits returns the bits of the permission p in the mode of "others".
+func permBits(p Perm) uint32 {
+	switch p {
+	case R:
+		return 4
+	case W:
+		return 2
+	case X:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestAccessACL(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "foo")
+
+	if err := os.WriteFile(filename, nil, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chown(filename, 0, 0); err != nil {
+		t.Skip("it requires the superuser")
+	}
+
+	// user::rw-, user:1000:rw-, group::r--, mask::rw-, other::---
+	entries := [][3]uint32{
+		{aclUserObj, 6, 0xffffffff},
+		{aclUser, 6, 1000},
+		{aclGroupObj, 4, 0xffffffff},
+		{aclMask, 6, 0xffffffff},
+		{aclOther, 0, 0xffffffff},
+	}
+	acl := make([]byte, 4+8*len(entries))
+	binary.LittleEndian.PutUint32(acl, 2) // version
+	for i, e := range entries {
+		b := acl[4+8*i:]
+		binary.LittleEndian.PutUint16(b, uint16(e[0]))
+		binary.LittleEndian.PutUint16(b[2:], uint16(e[1]))
+		binary.LittleEndian.PutUint32(b[4:], e[2])
+	}
+	if err := syscall.Setxattr(filename, xattrACLAccess, acl, 0); err != nil {
+		t.Skipf("ACLs not supported: %s", err)
+	}
+
+	info, err := NewInfo(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		uid, gid uint32
+		p        Perm
+		want     bool
+	}{
+		{1000, 1000, W, true},
+		{1000, 1000, X, false},
+		{1001, 1001, R, false},
+		{1001, 0, R, true},
+		{1001, 0, W, false},
+	} {
+		if got := info.canAs(v.uid, map[uint32]bool{v.gid: true}, v.p); got != v.want {
+			t.Errorf("uid %d, gid %d, perm %d: got %v, want %v", v.uid, v.gid, v.p, got, v.want)
+		}
+	}
+
+	if !info.CanRead() || !info.CanWrite() || info.CanExec() {
+		t.Errorf("superuser: got read %v, write %v, exec %v; want true, true, false",
+			info.CanRead(), info.CanWrite(), info.CanExec())
+	}
+	if ok, err := CanWrite(filename); err != nil || !ok {
+		t.Errorf("CanWrite: got %v, %v; want true", ok, err)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !linux
+// +build !linux
+
+package fileutil
+
+// can reports whether the process has the permission p on the file.
+// In this system, it is checked the owner permission.
+func (i Info) can(p Perm) bool { return i.OwnerHas(p) }
+
+// canAccess reports whether the process has the permission p on the named file.
+func canAccess(name string, p Perm) (bool, error) {
+	i, err := NewInfo(name)
+	if err != nil {
+		return false, err
+	}
+	return i.can(p), nil
+}
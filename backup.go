@@ -7,16 +7,47 @@
 package fileutil
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
-const suffixBackup = "+[1-9]~" // Suffix pattern added to backup's file name.
+const (
+	suffixBackup = "+[1-9]~" // Suffix pattern added to backup's file name.
+	suffixIndex  = "+~"      // Suffix added to the file name of the index of backups.
+)
 
 // DoBackup makes a backup at files used at both functions 'Copy()' and 'Overwrite()'.
+// It is the value by default; use the option WithBackup to set it at every call.
 var DoBackup = true
 
+// A BackupResult reports what has been done by a backup.
+type BackupResult uint8
+
+// Results of a backup.
+const (
+	BackupSkipped   BackupResult = iota // The file does not exist or it is empty.
+	BackupCreated                       // A new backup was created.
+	BackupUnchanged                     // The latest backup has the same content.
+)
+
+func (r BackupResult) String() string {
+	switch r {
+	case BackupSkipped:
+		return "skipped"
+	case BackupCreated:
+		return "created"
+	case BackupUnchanged:
+		return "unchanged"
+	}
+	return fmt.Sprintf("BackupResult(%d)", r)
+}
+
 // Backup creates a backup of the named file.
 //
 // The schema used for the new name is: {name}\+[1-9]~
@@ -24,38 +55,359 @@ var DoBackup = true
 //   + : Character used to separate the file name from rest.
 //   number: A number from 1 to 9, using rotation.
 //   ~ : To indicate that it is a backup, just like it is used in Unix systems.
+//
+// The backup keeps the metadata of the file (see copyMeta), as far as the
+// process has privileges.
+// It is not created a new backup when the latest one has the same content.
+//
+// The order of the backups, and the time when they were created, are recorded
+// at the file {name}+~, since the metadata of a backup can be changed.
 func Backup(filename string) error {
+	_, err := BackupR(filename)
+	return err
+}
+
+// BackupR is like Backup, but it also reports what has been done.
+func BackupR(filename string) (BackupResult, error) {
+	return newOptions(nil).backupFile(filename)
+}
+
+// backupFile creates a backup of the named file, using the logger in o.
+func (o *options) backupFile(filename string) (BackupResult, error) {
 	// Check if it is empty
 	info, err := os.Stat(filename)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return BackupSkipped, nil
 		}
-		return err
+		return BackupSkipped, err
 	}
 	if info.Size() == 0 {
-		return nil
+		return BackupSkipped, nil
 	}
 
-	files, err := filepath.Glob(filename + suffixBackup)
+	lastFile, lastInfo, err := latestBackup(filename)
 	if err != nil {
-		return err
+		return BackupSkipped, err
 	}
 
 	// Number rotation
-	numBackup := byte(1)
+	numBackup := byte('1')
 
-	if len(files) != 0 {
-		lastFile := files[len(files)-1]
-		numBackup = lastFile[len(lastFile)-2] + 1 // next number
+	if lastFile != "" {
+		same, err := sameContent(filename, info, lastFile, lastInfo)
+		if err != nil {
+			return BackupSkipped, err
+		}
+		if same {
+			o.log.Printf("Backup %q unchanged", lastFile)
+			return BackupUnchanged, nil
+		}
 
+		numBackup = lastFile[len(lastFile)-2] + 1 // next number
 		if numBackup > '9' {
 			numBackup = '1'
 		}
-	} else {
-		numBackup = '1'
 	}
 
-	Log.Print("Creating backup")
-	return Copy(filename, fmt.Sprintf("%s+%s~", filename, string(numBackup)))
+	o.log.Print("Creating backup")
+	ob := &options{preserve: PreserveAll, atomic: o.atomic, sync: o.sync, log: o.log}
+	if err = ob.copyFile(filename, fmt.Sprintf("%s+%s~", filename, string(numBackup))); err != nil {
+		return BackupSkipped, err
+	}
+	if err = o.addIndex(filename, numBackup, info.Mode().Perm()); err != nil {
+		return BackupCreated, err
+	}
+
+	if o.retentionSet {
+		if _, err = o.pruneBackups(filename, o.retention, false); err != nil {
+			return BackupCreated, err
+		}
+	}
+	return BackupCreated, nil
+}
+
+// Restore restores the named file from its latest backup, preserving the
+// metadata stored in the backup, unless it is set by the option WithPreserve.
+// It makes a backup of the current file if the global variable 'DoBackup' is
+// set to true, unless it is set by the option WithBackup.
+func Restore(filename string, opt ...Option) error {
+	lastFile, _, err := latestBackup(filename)
+	if err != nil {
+		return err
+	}
+	if lastFile == "" {
+		return &os.PathError{Op: "restore", Path: filename, Err: os.ErrNotExist}
+	}
+
+	o := newOptions(opt)
+	if !o.preserveSet {
+		o.preserve = PreserveAll
+	}
+
+	if o.backup {
+		if _, err = o.backupFile(filename); err != nil {
+			return err
+		}
+	}
+
+	o.log.Printf("Restoring backup %q", lastFile)
+	return o.copyFile(lastFile, filename)
+}
+
+// Retention represents the policy used to keep the backups of a file.
+// A field with value zero is not used.
+type Retention struct {
+	Last     int   // Keep the last N backups.
+	Daily    int   // Keep the latest backup of every day, for the last D days.
+	MaxBytes int64 // Maximum size of all backups kept.
+}
+
+// WithRetention sets the retention policy applied after of creating a backup:
+// the backups of a file at Backup and the functions which make backups, and
+// the snapshots at BackupDir. By default, the backups of a file are rotated
+// between 9 names, and they are kept the last 9 snapshots.
+func WithRetention(r Retention) Option {
+	return func(o *options) {
+		o.retention, o.retentionSet = r, true
+	}
+}
+
+// PruneReport represents the backups kept and deleted by PruneBackups and
+// PruneSnapshots.
+type PruneReport struct {
+	Kept    []string // Backups kept, from the newest.
+	Deleted []string // Backups deleted, from the newest.
+	Freed   int64    // Size of the backups deleted.
+}
+
+// PruneBackups deletes the backups of the named file which are not kept by the
+// retention policy r. A backup is kept if it is one of the last r.Last backups,
+// or if it is the latest backup of its day in the last r.Daily days; if both
+// fields are zero, all backups are kept. Then, it is applied r.MaxBytes,
+// deleting the oldest backups kept until their total size is not greater; the
+// newest backup is never deleted by it.
+//
+// If dryRun is true, it only reports what would be deleted.
+func PruneBackups(filename string, r Retention, dryRun bool) (*PruneReport, error) {
+	return newOptions(nil).pruneBackups(filename, r, dryRun)
+}
+
+// pruneBackups deletes the backups of the named file like PruneBackups, using
+// the logger in o.
+func (o *options) pruneBackups(filename string, r Retention, dryRun bool) (*PruneReport, error) {
+	backups, err := listBackups(filename)
+	if err != nil {
+		return nil, err
+	}
+	return o.prune(backups, r, dryRun, os.Remove)
+}
+
+// pruneEntry represents a backup, of a file or directory, to prune.
+type pruneEntry struct {
+	name string
+	time time.Time // time of creation
+	size int64
+}
+
+// prune removes, using the function remove, the backups which are not kept by
+// the retention policy r (see PruneBackups). The backups are sorted from the
+// newest.
+func (o *options) prune(backups []pruneEntry, r Retention, dryRun bool, remove func(string) error) (*PruneReport, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	lastDay := time.Time{}
+	total, full := int64(0), false
+	report := new(PruneReport)
+
+	for i, b := range backups {
+		keep := r.Last == 0 && r.Daily == 0
+
+		if i < r.Last {
+			keep = true
+		}
+		if r.Daily > 0 {
+			t := b.time.Local()
+			day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+
+			if !day.Equal(lastDay) && day.After(today.AddDate(0, 0, -r.Daily)) {
+				keep = true
+			}
+			lastDay = day
+		}
+		if keep && i > 0 && r.MaxBytes > 0 && (full || total+b.size > r.MaxBytes) {
+			keep, full = false, true
+		}
+
+		if keep {
+			total += b.size
+			report.Kept = append(report.Kept, b.name)
+			continue
+		}
+
+		report.Deleted = append(report.Deleted, b.name)
+		report.Freed += b.size
+
+		if dryRun {
+			o.log.Printf("Backup %q would be removed", b.name)
+			continue
+		}
+		o.log.Printf("Removing backup %q", b.name)
+		if err := remove(b.name); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// latestBackup returns the name and information of the backup of filename
+// created more recently. The name is empty if there is not any backup.
+func latestBackup(filename string) (name string, info os.FileInfo, err error) {
+	backups, err := listBackups(filename)
+	if err != nil || len(backups) == 0 {
+		return "", nil, err
+	}
+
+	name = backups[0].name
+	if info, err = os.Stat(name); err != nil {
+		return "", nil, err
+	}
+	return name, info, nil
+}
+
+// listBackups returns the backups of filename, from the newest, in the order
+// recorded at its index. The backups which are not in the index, made before of
+// using it, are the oldest ones, sorted by their change time.
+func listBackups(filename string) ([]pruneEntry, error) {
+	files, err := filepath.Glob(filename + suffixBackup)
+	if err != nil {
+		return nil, err
+	}
+	index, err := readIndex(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := make(map[byte]int, len(index)) // position in the index, from 1
+	for i, e := range index {
+		pos[e.num] = i + 1
+	}
+
+	backups := make([]pruneEntry, 0, len(files))
+	order := make(map[string]int, len(files))
+
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		num := f[len(f)-2]
+		t := changeTime(fi)
+		if p := pos[num]; p != 0 {
+			t = index[p-1].time
+		}
+		backups = append(backups, pruneEntry{f, t, fi.Size()})
+		order[f] = pos[num]
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		pi, pj := order[backups[i].name], order[backups[j].name]
+		if pi != pj {
+			return pi > pj
+		}
+		// Out of the index; at equal time, the rotation number decides.
+		if backups[i].time.Equal(backups[j].time) {
+			return backups[i].name > backups[j].name
+		}
+		return backups[i].time.After(backups[j].time)
+	})
+	return backups, nil
+}
+
+// indexEntry represents a backup recorded at the index of backups.
+type indexEntry struct {
+	num  byte      // rotation number
+	time time.Time // time of creation
+}
+
+// readIndex returns the backups recorded at the index of filename, from the
+// oldest. The lines which are not valid are skipped.
+func readIndex(filename string) ([]indexEntry, error) {
+	b, err := os.ReadFile(filename + suffixIndex)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var index []indexEntry
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != 1 || fields[0][0] < '1' || fields[0][0] > '9' {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, fields[1])
+		if err != nil {
+			continue
+		}
+		index = append(index, indexEntry{fields[0][0], t})
+	}
+	return index, nil
+}
+
+// addIndex records the backup with the rotation number num as the newest one at
+// the index of filename, removing the backups which do not exist. The index is
+// created with the permissions perm.
+func (o *options) addIndex(filename string, num byte, perm os.FileMode) error {
+	index, err := readIndex(filename)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for _, e := range index {
+		if e.num == num {
+			continue
+		}
+		if _, err = os.Lstat(fmt.Sprintf("%s+%s~", filename, string(e.num))); err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s\n", string(e.num), e.time.Format(time.RFC3339Nano))
+	}
+	fmt.Fprintf(&b, "%s %s\n", string(num), time.Now().UTC().Format(time.RFC3339Nano))
+
+	oi := &options{perm: perm, permSet: true, atomic: true, sync: o.sync, log: o.log}
+	return oi.writeFile(filename+suffixIndex, func(file *os.File) error {
+		_, err := file.WriteString(b.String())
+		return err
+	})
+}
+
+// sameContent reports whether both files have the same content, comparing at
+// first their size and then their hash.
+func sameContent(name1 string, info1 os.FileInfo, name2 string, info2 os.FileInfo) (bool, error) {
+	if info1.Size() != info2.Size() {
+		return false, nil
+	}
+
+	sum1, err := hashFile(name1)
+	if err != nil {
+		return false, err
+	}
+	sum2, err := hashFile(name2)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(sum1, sum2), nil
+}
+
+// hashFile returns the SHA-256 checksum of the named file.
+func hashFile(name string) ([]byte, error) {
+	return sumFile(name, sha256.New())
 }
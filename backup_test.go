@@ -0,0 +1,214 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackupUnchanged(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "foo")
+
+	if err := os.WriteFile(filename, []byte("foo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []BackupResult{BackupCreated, BackupUnchanged} {
+		res, err := BackupR(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != want {
+			t.Errorf("#%d: got %s, want %s", i, res, want)
+		}
+	}
+
+	files, err := filepath.Glob(filename + suffixBackup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("got %d backups, want 1", len(files))
+	}
+
+	if err = os.WriteFile(filename, []byte("bar\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := BackupR(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != BackupCreated {
+		t.Errorf("got %s, want %s", res, BackupCreated)
+	}
+	if _, err = os.Stat(filename + "+2~"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "foo")
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if err := os.WriteFile(filename, []byte("foo\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filename, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filename, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := Backup(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	fi, err := os.Stat(filename + "+1~")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0640 {
+		t.Errorf("got mode %v, want %v", fi.Mode().Perm(), os.FileMode(0640))
+	}
+	if !fi.ModTime().Equal(modTime) {
+		t.Errorf("got time %v, want %v", fi.ModTime(), modTime)
+	}
+
+	if err = os.WriteFile(filename, []byte("bar\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chmod(filename, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = Restore(filename, WithBackup(true), WithAtomic(true), WithSync(SyncDir)); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "foo\n" {
+		t.Errorf("got %q, want %q", b, "foo\n")
+	}
+	if fi, err = os.Stat(filename); err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0640 {
+		t.Errorf("got mode %v, want %v", fi.Mode().Perm(), os.FileMode(0640))
+	}
+
+	// The file restored was backed up before.
+	if b, err = os.ReadFile(filename + "+2~"); err != nil || string(b) != "bar\n" {
+		t.Errorf("got backup %q, %v; want %q", b, err, "bar\n")
+	}
+	if files, _ := filepath.Glob(filename + "*" + prefixTemp + "*"); len(files) != 0 {
+		t.Errorf("temporary files not removed: %q", files)
+	}
+}
+
+func TestBackupOrder(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "foo")
+
+	for i := 1; i <= 12; i++ {
+		if err := os.WriteFile(filename, []byte(strings.Repeat("x", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Backup(filename); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The order does not depend on the metadata of the backups.
+	if err := os.Chmod(filename+"+5~", 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filename, []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(filename, WithBackup(false)); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(filename); len(b) != 12 {
+		t.Errorf("got %d bytes restored, want 12", len(b))
+	}
+
+	if err := os.WriteFile(filename, []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Backup(filename); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(filename + "+4~"); string(b) != "foo" {
+		t.Errorf("got backup %q, want %q", b, "foo")
+	}
+	if b, _ := os.ReadFile(filename + "+5~"); len(b) != 5 {
+		t.Errorf("got %d bytes at backup +5~, want 5", len(b))
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "foo")
+
+	for _, v := range []string{"1\n", "22\n", "333\n", "4444\n"} {
+		if err := os.WriteFile(filename, []byte(v), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Backup(filename); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := PruneBackups(filename, Retention{Last: 3}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Kept) != 3 || len(report.Deleted) != 1 {
+		t.Fatalf("dry-run: got %d kept and %d deleted, want 3 and 1",
+			len(report.Kept), len(report.Deleted))
+	}
+	if _, err = os.Stat(report.Deleted[0]); err != nil {
+		t.Errorf("dry-run: %s", err)
+	}
+
+	// The last backups have 5 and 4 bytes.
+	report, err = PruneBackups(filename, Retention{MaxBytes: 9}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Kept) != 2 || report.Freed != 5 {
+		t.Fatalf("got %d kept and %d bytes freed, want 2 and 5", len(report.Kept), report.Freed)
+	}
+	for _, v := range report.Deleted {
+		if _, err = os.Stat(v); !os.IsNotExist(err) {
+			t.Errorf("backup %q not removed", v)
+		}
+	}
+	// Applied after of creating a backup.
+	if err = os.WriteFile(filename, []byte("55555\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = OverwriteString(filename, "666666\n", WithBackup(true), WithRetention(Retention{Last: 1})); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filename + suffixBackup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("got backups %q, want 1", files)
+	}
+	if b, err := os.ReadFile(files[0]); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "55555\n" {
+		t.Errorf("got backup %q, want %q", b, "55555\n")
+	}
+}
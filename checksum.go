@@ -0,0 +1,82 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+// A ChecksumError is returned when the checksum of a copied file does not
+// match the checksum of its source.
+type ChecksumError struct {
+	Name string // Copied file.
+	Want []byte // Checksum of the source file.
+	Got  []byte // Checksum of the copied file.
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch at %q: got %x, want %x", e.Name, e.Got, e.Want)
+}
+
+// WithVerify sets whether the copied file is read again to check its checksum
+// against the one of the source file. When it does not match, the copied file
+// is removed and it is returned a *ChecksumError.
+//
+// It is used SHA-256, unless it is used CopySum.
+func WithVerify(ok bool) Option {
+	return func(o *options) { o.verify = ok }
+}
+
+// CopySum is like Copy, but it also returns the checksum of the source file,
+// computed with a hash returned by newHash (like sha256.New) while it is
+// copied. With the option WithVerify, that checksum is checked against the
+// copied file.
+func CopySum(source, dest string, newHash func() hash.Hash, opt ...Option) ([]byte, error) {
+	o := newOptions(opt)
+	o.newHash = newHash
+
+	return o.copy(source, dest)
+}
+
+// verifySum checks that the checksum of the named file, computed with h, is
+// sum. Else, the file is removed.
+func (o *options) verifySum(name string, h hash.Hash, sum []byte) error {
+	got, err := sumFile(name, h)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(got, sum) {
+		return nil
+	}
+
+	if err = os.Remove(name); err != nil {
+		o.log.Printf("File %q not removed: %s", name, err)
+	}
+	return &ChecksumError{Name: name, Want: sum, Got: got}
+}
+
+// sumFile returns the checksum of the named file, computed with h.
+func sumFile(name string, h hash.Hash) (sum []byte, err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err2 := f.Close(); err2 != nil && err == nil {
+			err = err2
+		}
+	}()
+
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopySum(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "dest")
+
+	sum, err := CopySum(FILENAME, dest, sha256.New, WithVerify(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(FILENAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := sha256.Sum256(b); !bytes.Equal(sum, want[:]) {
+		t.Errorf("got checksum %x, want %x", sum, want)
+	}
+
+	// A copy which does not match.
+	err = newOptions(nil).verifySum(dest, sha256.New(), []byte("foo"))
+
+	var errSum *ChecksumError
+	if !errors.As(err, &errSum) {
+		t.Fatalf("got error %v, want *ChecksumError", err)
+	}
+	if _, err = os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("file %q not removed", dest)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Bits which can be changed for every class of users at a symbolic mode.
+const (
+	classUser  = modeSetuid | modeROwner | modeWOwner | modeXOwner
+	classGroup = modeSetgid | modeRGroup | modeWGroup | modeXGroup
+	classOther = modeSticky | modeROthers | modeWOthers | modeXOthers
+)
+
+// A ModeSpec represents a mode, in symbolic or octal form, to apply to files
+// like it is done by the command chmod.
+type ModeSpec struct {
+	spec    string
+	octal   bool
+	mode    uint32 // mode in octal form
+	clauses []modeClause
+}
+
+// modeClause represents an operation at a symbolic mode.
+type modeClause struct {
+	who  uint32 // bits of the classes of users
+	op   byte   // '+', '-' or '='
+	perm string // permissions, from "rwxXst", or a class of users, from "ugo"
+}
+
+// ParseMode parses a mode in octal form, like "0755" or "4755", or in symbolic
+// form, like "u+rwx,g-w,o=" or "a+X", as it is described at `man 1 chmod`.
+//
+// At the symbolic form, the permission 'X' is execute only if the file is a
+// directory or it already has execute permission for some user. When the
+// class of users is not given, it is used 'a'; the umask is not applied.
+//
+// Like chmod, the set-user-ID and set-group-ID bits of a directory are kept
+// unless they are given explicitly: at the octal form, they are cleared with
+// more than 4 digits, like "00755"; at the symbolic form, with "u-s" or "g-s".
+func ParseMode(spec string) (*ModeSpec, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("invalid mode: %q", spec)
+	}
+
+	if spec[0] >= '0' && spec[0] <= '7' {
+		mode, err := strconv.ParseUint(spec, 8, 32)
+		if err != nil || mode > 07777 {
+			return nil, fmt.Errorf("invalid mode: %q", spec)
+		}
+		return &ModeSpec{spec: spec, octal: true, mode: uint32(mode)}, nil
+	}
+
+	m := &ModeSpec{spec: spec}
+
+	for _, clause := range strings.Split(spec, ",") {
+		var who uint32
+		i := 0
+
+	Who:
+		for ; i < len(clause); i++ {
+			switch clause[i] {
+			case 'u':
+				who |= classUser
+			case 'g':
+				who |= classGroup
+			case 'o':
+				who |= classOther
+			case 'a':
+				who |= classUser | classGroup | classOther
+			default:
+				break Who
+			}
+		}
+		if who == 0 {
+			who = classUser | classGroup | classOther
+		}
+		if i == len(clause) {
+			return nil, fmt.Errorf("invalid mode: %q", spec)
+		}
+
+		for i < len(clause) {
+			op := clause[i]
+			if op != '+' && op != '-' && op != '=' {
+				return nil, fmt.Errorf("invalid mode: %q", spec)
+			}
+			i++
+
+			start := i
+			for ; i < len(clause) && strings.IndexByte("+-=", clause[i]) == -1; i++ {
+			}
+			perm := clause[start:i]
+
+			if len(perm) == 1 && strings.IndexByte("ugo", perm[0]) != -1 {
+				// Copy of the permissions of a class of users.
+			} else if strings.Trim(perm, "rwxXst") != "" {
+				return nil, fmt.Errorf("invalid mode: %q", spec)
+			}
+
+			m.clauses = append(m.clauses, modeClause{who, op, perm})
+		}
+	}
+
+	return m, nil
+}
+
+// String returns the mode as it was parsed.
+func (m *ModeSpec) String() string { return m.spec }
+
+// Apply returns the mode got at applying m to the given mode.
+func (m *ModeSpec) Apply(mode os.FileMode) os.FileMode {
+	bits := toUnixMode(mode)
+
+	if m.octal {
+		if mode.IsDir() && len(m.spec) < 5 {
+			return mode&^modeMask | fromUnixMode(m.mode|bits&(modeSetuid|modeSetgid))
+		}
+		return mode&^modeMask | fromUnixMode(m.mode)
+	}
+
+	for _, c := range m.clauses {
+		var perm uint32
+
+		switch c.perm {
+		case "u":
+			perm = (bits >> 6 & 07) * 0111
+		case "g":
+			perm = (bits >> 3 & 07) * 0111
+		case "o":
+			perm = (bits & 07) * 0111
+		default:
+			for _, r := range c.perm {
+				switch r {
+				case 'r':
+					perm |= 0444
+				case 'w':
+					perm |= 0222
+				case 'x':
+					perm |= 0111
+				case 'X':
+					if mode.IsDir() || bits&0111 != 0 {
+						perm |= 0111
+					}
+				case 's':
+					perm |= modeSetuid | modeSetgid
+				case 't':
+					perm |= modeSticky
+				}
+			}
+		}
+		perm &= c.who
+
+		switch c.op {
+		case '+':
+			bits |= perm
+		case '-':
+			bits &^= perm
+		case '=':
+			keep := uint32(0)
+			if mode.IsDir() {
+				keep = (modeSetuid | modeSetgid) &^ perm
+			}
+			bits = bits&^(c.who&^keep) | perm
+		}
+	}
+
+	return mode&^modeMask | fromUnixMode(bits)
+}
+
+// Chmod changes the mode of the named file applying the mode 'spec', in octal
+// or symbolic form (see ParseMode).
+func Chmod(name, spec string) error {
+	m, err := ParseMode(spec)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(name, m.Apply(info.Mode()))
+}
+
+// ChmodRecursive changes the mode of the directory tree at 'dir', like at
+// Chmod. The symbolic links are skipped.
+func ChmodRecursive(dir, spec string) error {
+	m, err := ParseMode(spec)
+	if err != nil {
+		return err
+	}
+
+	return filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		return os.Chmod(path, m.Apply(fi.Mode()))
+	})
+}
+
+// * * *
+
+// toUnixMode returns the permissions and special modes of mode in octal form.
+func toUnixMode(mode os.FileMode) uint32 {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= modeSetuid
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= modeSetgid
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= modeSticky
+	}
+	return bits
+}
+
+// fromUnixMode returns the os.FileMode of the permissions and special modes in
+// octal form.
+func fromUnixMode(bits uint32) os.FileMode {
+	mode := os.FileMode(bits) & os.ModePerm
+	if bits&modeSetuid != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&modeSetgid != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&modeSticky != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
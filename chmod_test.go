@@ -0,0 +1,110 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	for _, v := range []struct {
+		spec string
+		mode os.FileMode
+		want os.FileMode
+	}{
+		{"0755", 0600, 0755},
+		{"4755", 0600, 0755 | os.ModeSetuid},
+		{"u+rwx,g-w,o=", 0666, 0740},
+		{"a+X", 0644, 0644},
+		{"a+X", 0744, 0755},
+		{"a+X", os.ModeDir | 0600, os.ModeDir | 0711},
+		{"go=u-w", 0750, 0755 &^ 0022},
+		{"+t", os.ModeDir | 0777, os.ModeDir | os.ModeSticky | 0777},
+		{"u+s,g+s", 0755, 0755 | os.ModeSetuid | os.ModeSetgid},
+		{"o+s", 0755, 0755},
+		{"=r", 0777, 0444},
+		{"u=rw,go=r", 0, 0644},
+
+		// The set-user-ID and set-group-ID bits of a directory are kept.
+		{"755", os.ModeDir | os.ModeSetgid | 0700, os.ModeDir | os.ModeSetgid | 0755},
+		{"0", os.ModeDir | os.ModeSetuid | 0700, os.ModeDir | os.ModeSetuid},
+		{"2755", os.ModeDir | os.ModeSetuid | 0700, os.ModeDir | os.ModeSetuid | os.ModeSetgid | 0755},
+		{"00755", os.ModeDir | os.ModeSetgid | 0700, os.ModeDir | 0755},
+		{"1755", os.ModeDir | os.ModeSetgid | 0700, os.ModeDir | os.ModeSetgid | os.ModeSticky | 0755},
+		{"755", os.ModeSetgid | 0700, 0755},
+		{"g=u", os.ModeDir | os.ModeSetgid | 0750, os.ModeDir | os.ModeSetgid | 0770},
+		{"go=", os.ModeDir | os.ModeSetgid | 0775, os.ModeDir | os.ModeSetgid | 0700},
+		{"=r", os.ModeDir | os.ModeSetuid | os.ModeSticky | 0777, os.ModeDir | os.ModeSetuid | 0444},
+		{"g=rx", os.ModeSetgid | 0770, 0750},
+		{"g-s", os.ModeDir | os.ModeSetgid | 0770, os.ModeDir | 0770},
+		{"u=rwxs", os.ModeDir | 0700, os.ModeDir | os.ModeSetuid | 0700},
+	} {
+		m, err := ParseMode(v.spec)
+		if err != nil {
+			t.Errorf("%q: %s", v.spec, err)
+			continue
+		}
+		if got := m.Apply(v.mode); got != v.want {
+			t.Errorf("%q at %v: got %v, want %v", v.spec, v.mode, got, v.want)
+		}
+	}
+
+	for _, spec := range []string{"", "8", "77777", "u", "u+z", "k+r", "u+r,"} {
+		if _, err := ParseMode(spec); err == nil {
+			t.Errorf("%q: expected error", spec)
+		}
+	}
+}
+
+func TestChmod(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	filename := filepath.Join(sub, "foo")
+
+	if err := os.Mkdir(sub, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ChmodRecursive(sub, "go+rX"); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{sub: "0755", filename: "0644"} {
+		info, err := NewInfo(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Octal() != want {
+			t.Errorf("%s: got mode %s, want %s", name, info.Octal(), want)
+		}
+	}
+
+	if err := Chmod(sub, "1777"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := NewInfo(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Symbolic(); got != "drwxrwxrwt" {
+		t.Errorf("got mode %s, want drwxrwxrwt", got)
+	}
+
+	if err = Chmod(filename, "u+s,o-r"); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = NewInfo(filename); err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Symbolic(); got != "-rwSr-----" {
+		t.Errorf("got mode %s, want -rwSr-----", got)
+	}
+}
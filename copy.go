@@ -0,0 +1,46 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"io"
+	"os"
+)
+
+// A CopyMethod value is the method used to copy the content of a file.
+type CopyMethod uint8
+
+// Methods used to copy the content of a file.
+// At Linux, a method falls back to the next one when it is not supported.
+const (
+	CopyAuto    CopyMethod = iota // Reflink, sparse copy for files with holes, copy_file_range and streaming.
+	CopyReflink                   // Reflink, copy_file_range and streaming.
+	CopySparse                    // Sparse copy, copy_file_range and streaming.
+	CopyRange                     // copy_file_range and streaming.
+	CopyStream                    // Read and write the content in user space.
+)
+
+// WithCopyMethod sets the method used to copy the content of a file.
+// By default, it is used CopyAuto.
+//
+// The reflink (at filesystems like Btrfs and XFS) makes a copy which shares
+// the data blocks with the source file until some of them is modified; the
+// sparse copy keeps the holes of the file; and copy_file_range copies the
+// content without passing it through the user space.
+//
+// It is always used the streaming when it is computed a checksum while the
+// file is copied.
+func WithCopyMethod(m CopyMethod) Option {
+	return func(o *options) { o.method = m }
+}
+
+// copyStream copies src to dst reading and writing in user space.
+func copyStream(dst, src *os.File) error {
+	// Hide the methods 'ReadFrom' and 'WriteTo' used by the package io.
+	_, err := io.Copy(struct{ io.Writer }{dst}, struct{ io.Reader }{src})
+	return err
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+)
+
+const (
+	ioctlFICLONE = 0x40049409 // ioctl to clone a file: `man 2 ioctl_ficlone`
+
+	seekData = 3 // SEEK_DATA: `man 2 lseek`
+	seekHole = 4 // SEEK_HOLE
+)
+
+// copyData copies the content of src, described by info, to dst, which is
+// empty. It tries the method m, falling back to the next ones when the kernel
+// or the filesystem do not support it: reflink, sparse copy, copy_file_range
+// and streaming.
+func copyData(dst, src *os.File, info os.FileInfo, m CopyMethod) error {
+	if m == CopyStream {
+		return copyStream(dst, src)
+	}
+
+	try := func(fn func() error) (done bool, err error) {
+		if err = fn(); err == nil || !isFallback(err) {
+			return true, err
+		}
+		return false, rewind(dst, src)
+	}
+
+	if m == CopyAuto || m == CopyReflink {
+		if done, err := try(func() error { return reflink(dst, src) }); done || err != nil {
+			return err
+		}
+	}
+	if m == CopySparse || m == CopyAuto && isSparse(info) {
+		if done, err := try(func() error { return copySparse(dst, src, info.Size()) }); done || err != nil {
+			return err
+		}
+	}
+	if done, err := try(func() error { return copyRange(dst, src) }); done || err != nil {
+		return err
+	}
+	return copyStream(dst, src)
+}
+
+// rewind sets both files at their start, and truncates dst.
+func rewind(dst, src *os.File) error {
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := dst.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return dst.Truncate(0)
+}
+
+// reflink clones src into dst, so both files share the same data blocks until
+// some of them is modified. It is supported by filesystems like Btrfs and XFS.
+func reflink(dst, src *os.File) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dst.Fd(), ioctlFICLONE, src.Fd())
+	if errno != 0 {
+		return &os.PathError{Op: "ficlone", Path: dst.Name(), Err: errno}
+	}
+	return nil
+}
+
+// errRangeEmpty is returned when copy_file_range copies nothing at the first
+// call, so the empty files are copied by streaming.
+var errRangeEmpty = errors.New("nothing copied")
+
+// copyRange copies src to dst into the kernel, using copy_file_range.
+func copyRange(dst, src *os.File) error {
+	if sysCopyFileRange == 0 {
+		return &os.PathError{Op: "copy_file_range", Path: dst.Name(), Err: syscall.ENOSYS}
+	}
+	const maxLen = 1 << 30
+
+	for first := true; ; first = false {
+		n, _, errno := syscall.Syscall6(sysCopyFileRange, src.Fd(), 0, dst.Fd(), 0, maxLen, 0)
+		if errno != 0 {
+			return &os.PathError{Op: "copy_file_range", Path: dst.Name(), Err: errno}
+		}
+		if n == 0 {
+			// Before of Linux 5.19, it copies nothing from files of procfs and
+			// sysfs, whose size is zero, so it is not known if it is the end.
+			if first {
+				return &os.PathError{Op: "copy_file_range", Path: dst.Name(), Err: errRangeEmpty}
+			}
+			return nil
+		}
+	}
+}
+
+// copySparse copies the data of src to dst keeping the holes, so a sparse
+// file does not use more space at the copy.
+func copySparse(dst, src *os.File, size int64) error {
+	srcFd := int(src.Fd())
+
+	for off := int64(0); off < size; {
+		data, err := syscall.Seek(srcFd, off, seekData)
+		if err != nil {
+			if err == syscall.ENXIO { // no more data
+				break
+			}
+			return &os.PathError{Op: "seek", Path: src.Name(), Err: err}
+		}
+		hole, err := syscall.Seek(srcFd, data, seekHole)
+		if err != nil {
+			return &os.PathError{Op: "seek", Path: src.Name(), Err: err}
+		}
+
+		if _, err = src.Seek(data, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err = dst.Seek(data, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err = io.CopyN(dst, src, hole-data); err != nil {
+			return err
+		}
+		off = hole
+	}
+
+	return dst.Truncate(size)
+}
+
+// isSparse reports whether the file described by info has holes.
+func isSparse(info os.FileInfo) bool {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return ok && st.Blocks*512 < info.Size()
+}
+
+// isFallback reports whether the error got by a copy method means that it is
+// not supported, so it has to be used another one.
+func isFallback(err error) bool {
+	if errors.Is(err, errRangeEmpty) {
+		return true
+	}
+	for _, errno := range []syscall.Errno{
+		syscall.ENOSYS, syscall.EXDEV, syscall.EINVAL, syscall.ENOTTY,
+		syscall.EOPNOTSUPP, syscall.ENOTSUP, syscall.EBADF, syscall.EPERM,
+	} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestCopyMethod(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	const size = 4 << 20
+
+	// A sparse file, with data at the middle and at the end.
+	f, err := os.Create(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.WriteAt([]byte("foo"), size/2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.WriteAt([]byte("bar"), size-3); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range []CopyMethod{CopyAuto, CopyReflink, CopySparse, CopyRange, CopyStream} {
+		dest := filepath.Join(dir, "dest")
+
+		if err = Copy(source, dest, WithBackup(false), WithCopyMethod(m)); err != nil {
+			t.Fatalf("method %d: %s", m, err)
+		}
+		got, err := os.ReadFile(dest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("method %d: the copy differs", m)
+		}
+
+		if m == CopySparse {
+			fi, err := os.Stat(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !isSparse(fi) {
+				t.Errorf("method %d: got %d blocks for a sparse file",
+					m, fi.Sys().(*syscall.Stat_t).Blocks)
+			}
+		}
+		if err = os.Remove(dest); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCopyProcFile(t *testing.T) {
+	const source = "/proc/version"
+
+	want, err := os.ReadFile(source)
+	if err != nil {
+		t.Skip(err)
+	}
+	dest := filepath.Join(t.TempDir(), "version")
+
+	for _, m := range []CopyMethod{CopyAuto, CopyRange} {
+		if err = Copy(source, dest, WithBackup(false), WithCopyMethod(m)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(dest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) == 0 || !bytes.Equal(got, want) {
+			t.Errorf("method %d: got %q, want %q", m, got, want)
+		}
+	}
+
+	// An empty file is copied too.
+	empty := filepath.Join(t.TempDir(), "empty")
+	if err = os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = Copy(empty, dest, WithBackup(false), WithCopyMethod(CopyRange)); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(dest); err != nil || info.Size() != 0 {
+		t.Errorf("got %v, want an empty file", err)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !linux
+// +build !linux
+
+package fileutil
+
+import "os"
+
+// copyData copies the content of src, described by info, to dst.
+// In this system, it is always used the streaming.
+func copyData(dst, src *os.File, info os.FileInfo, m CopyMethod) error {
+	return copyStream(dst, src)
+}
@@ -0,0 +1,295 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	errLinkLoop   = errors.New("loop of symbolic links")
+	errIntoItself = errors.New("cannot copy a directory into itself")
+)
+
+// CopyDir copies the directory tree at 'source' to 'dest', preserving the mode
+// and times of files and directories, the symbolic links and the empty
+// directories; the rest of metadata can be set with the option WithPreserve.
+// If 'dest' already exists, the content of 'source' is copied into it.
+//
+// It is configured by the options WithFollowLinks, WithInclude, WithExclude,
+// WithConflict and WithWorkers. The named pipes, sockets and devices are
+// skipped.
+func CopyDir(source, dest string, opt ...Option) error {
+	return newOptions(opt).copyDir(source, dest)
+}
+
+// copyDir copies the directory tree at 'source' to 'dest'.
+func (o *options) copyDir(source, dest string) error {
+	o.preserve |= PreserveMode | PreserveTimes
+
+	for _, p := range append(o.include, o.exclude...) {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return err
+		}
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return &os.PathError{Op: "copydir", Path: source, Err: errNotDir}
+	}
+
+	// Else, the copy would be copied again until the path is too long.
+	srcPath, err := realPath(source)
+	if err != nil {
+		return err
+	}
+	dstPath, err := realPath(dest)
+	if err != nil {
+		return err
+	}
+	if isUnder(dstPath, srcPath) {
+		return &os.PathError{Op: "copydir", Path: dest, Err: errIntoItself}
+	}
+
+	c := &dirCopier{
+		o:      o,
+		jobs:   make(chan copyJob),
+		failed: make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < o.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range c.jobs {
+				if err := c.copyFile(job); err != nil {
+					c.fail(err)
+				}
+			}
+		}()
+	}
+
+	err = c.copyDir(source, dest, info, nil)
+	close(c.jobs)
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	if c.err != nil {
+		return c.err
+	}
+
+	// The times of the directories change when their files are created.
+	for i := len(c.dirs) - 1; i >= 0; i-- {
+		if err = o.copyMeta(c.dirs[i].src, c.dirs[i].dst, c.dirs[i].info); err != nil {
+			return err
+		}
+	}
+
+	o.log.Printf("Directory %q copied at %q", source, dest)
+	return nil
+}
+
+// copyJob represents a file to copy.
+type copyJob struct {
+	src, dst string
+	info     os.FileInfo
+}
+
+// dirCopier copies a directory tree.
+type dirCopier struct {
+	o    *options
+	jobs chan copyJob // regular files to copy by the workers
+	dirs []copyJob    // directories whose metadata is set at the end
+
+	mu     sync.Mutex
+	err    error         // first error got by a worker
+	failed chan struct{} // closed when a worker fails
+}
+
+// fail records the first error got by a worker.
+func (c *dirCopier) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err == nil {
+		c.err = err
+		close(c.failed)
+	}
+}
+
+// copyDir copies the directory src, described by info, to dst.
+// parents has the information of the parent directories, to detect loops.
+func (c *dirCopier) copyDir(src, dst string, info os.FileInfo, parents []os.FileInfo) error {
+	for _, p := range parents {
+		if os.SameFile(p, info) {
+			return &os.PathError{Op: "copydir", Path: src, Err: errLinkLoop}
+		}
+	}
+	parents = append(parents, info)
+
+	if err := os.Mkdir(dst, 0700); err != nil {
+		if fi, err2 := os.Stat(dst); err2 != nil || !fi.IsDir() {
+			return err
+		}
+	}
+	c.dirs = append(c.dirs, copyJob{src, dst, info})
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(src, entry.Name())
+		target := filepath.Join(dst, entry.Name())
+
+		fi, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 && c.o.followLinks {
+			if fi2, err := os.Stat(path); err == nil {
+				fi = fi2
+			} else {
+				c.o.log.Printf("Symbolic link %q copied as link: %s", path, err)
+			}
+		}
+
+		rel, err := filepath.Rel(c.dirs[0].src, path)
+		if err != nil {
+			return err
+		}
+		if matchAny(c.o.exclude, entry.Name(), rel) {
+			continue
+		}
+		if !fi.IsDir() && len(c.o.include) != 0 && !matchAny(c.o.include, entry.Name(), rel) {
+			continue
+		}
+
+		switch mode := fi.Mode(); {
+		case mode.IsDir():
+			if err = c.copyDir(path, target, fi, parents); err != nil {
+				return err
+			}
+
+		case mode&os.ModeSymlink != 0:
+			if err = c.copyLink(path, target); err != nil {
+				return err
+			}
+
+		case mode.IsRegular():
+			select {
+			case c.jobs <- copyJob{path, target, fi}:
+			case <-c.failed:
+				return nil // the error is returned by the worker
+			}
+
+		default:
+			c.o.log.Printf("File %q skipped: not a regular file", path)
+		}
+	}
+	return nil
+}
+
+// copyLink copies the symbolic link src to dst.
+func (c *dirCopier) copyLink(src, dst string) error {
+	link, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+
+	if ok, err := c.resolveConflict(dst); err != nil || !ok {
+		return err
+	}
+	if err = os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(link, dst)
+}
+
+// copyFile copies the regular file in job.
+func (c *dirCopier) copyFile(job copyJob) error {
+	if ok, err := c.resolveConflict(job.dst); err != nil || !ok {
+		return err
+	}
+
+	return c.o.copyFile(job.src, job.dst)
+}
+
+// resolveConflict applies the conflict policy if the named file exists,
+// reporting whether it has to be written.
+func (c *dirCopier) resolveConflict(name string) (bool, error) {
+	if c.o.conflict == ConflictOverwrite {
+		return true, nil
+	}
+	if _, err := os.Lstat(name); err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	if c.o.conflict == ConflictSkip {
+		c.o.log.Printf("File %q skipped: it exists", name)
+		return false, nil
+	}
+	if _, err := c.o.backupFile(name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// realPath returns the absolute path of the named file without symbolic links.
+// If the file does not exist, it is resolved the path of the parent directory
+// which exists.
+func realPath(name string) (string, error) {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+
+	rest := ""
+	for {
+		real, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(real, rest), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest), nil
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+// matchAny reports whether the base name or the relative path of a file match
+// any pattern.
+func matchAny(patterns []string, base, rel string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, base); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, rel); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCopyDir(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src")
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, dir := range []string{"sub/empty", "skip"} {
+		if err := os.MkdirAll(filepath.Join(src, dir), 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a.conf", "b.txt", "sub/c.conf", "skip/d.conf"} {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(name), 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(filepath.Join(src, "a.conf"), modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("a.conf", filepath.Join(src, "link.conf")); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(tmp, "dst")
+	err := CopyDir(src, dst,
+		WithInclude("*.conf"), WithExclude("skip"), WithWorkers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		name  string
+		exist bool
+	}{
+		{"a.conf", true},
+		{"b.txt", false},
+		{"sub/c.conf", true},
+		{"sub/empty", true},
+		{"skip", false},
+	} {
+		if _, err = os.Lstat(filepath.Join(dst, v.name)); (err == nil) != v.exist {
+			t.Errorf("%s: got exist %v, want %v", v.name, !v.exist, v.exist)
+		}
+	}
+
+	fi, err := os.Stat(filepath.Join(dst, "a.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0640 || !fi.ModTime().Equal(modTime) {
+		t.Errorf("got mode %v and time %v, want %v and %v",
+			fi.Mode().Perm(), fi.ModTime(), os.FileMode(0640), modTime)
+	}
+	if fi, err = os.Lstat(filepath.Join(dst, "link.conf")); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode()&os.ModeSymlink == 0 {
+		t.Error("symbolic link not copied as link")
+	}
+
+	// Conflicts
+	if err = os.WriteFile(filepath.Join(dst, "a.conf"), []byte("new"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err = CopyDir(src, dst, WithConflict(ConflictSkip)); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dst, "a.conf")); string(b) != "new" {
+		t.Errorf("ConflictSkip: got %q, want %q", b, "new")
+	}
+
+	if err = CopyDir(src, dst, WithConflict(ConflictBackup)); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dst, "a.conf")); string(b) != "a.conf" {
+		t.Errorf("ConflictBackup: got %q, want %q", b, "a.conf")
+	}
+	if b, _ := os.ReadFile(filepath.Join(dst, "a.conf+1~")); string(b) != "new" {
+		t.Errorf("ConflictBackup: got backup %q, want %q", b, "new")
+	}
+}
+
+func TestCopyDirIntoItself(t *testing.T) {
+	src := t.TempDir()
+	link := filepath.Join(t.TempDir(), "link")
+
+	if err := os.WriteFile(filepath.Join(src, "foo"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(src, link); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dest := range []string{
+		src,
+		filepath.Join(src, "sub"),
+		filepath.Join(src, "sub", "sub2"),
+		filepath.Join(link, "sub"),
+	} {
+		if err := CopyDir(src, dest); !errors.Is(err, errIntoItself) {
+			t.Errorf("%s: got error %v, want %v", dest, err, errIntoItself)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(src, "sub")); !os.IsNotExist(err) {
+		t.Errorf("directory created into the source: %v", err)
+	}
+
+	// A directory with the same prefix is not into the source.
+	if err := CopyDir(src, src+"-copy"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -34,13 +34,24 @@ type Editer struct {
 	file *os.File
 	buf  *bufio.ReadWriter
 	conf *ConfEditer
+	opt  *options
 }
 
 // NewEdit prepares a file to edit.
 // You must use 'Close()' to close the file.
-func NewEdit(filename string, conf *ConfEditer) (*Editer, error) {
-	if conf != nil && conf.Mode&ModBackup != 0 {
-		if err := Backup(filename); err != nil {
+//
+// The backup is done if the mode in conf has ModBackup, unless it is set by
+// the option WithBackup. At atomic mode (see WithAtomic), the functions which
+// rewrite the content, as Replace or Comment, write it into a new file which
+// replaces the named file.
+func NewEdit(filename string, conf *ConfEditer, opt ...Option) (*Editer, error) {
+	o := newOptions(opt)
+	if !o.backupSet {
+		o.backup = conf != nil && conf.Mode&ModBackup != 0
+	}
+
+	if o.backup {
+		if _, err := o.backupFile(filename); err != nil {
 			return nil, err
 		}
 	}
@@ -54,6 +65,7 @@ func NewEdit(filename string, conf *ConfEditer) (*Editer, error) {
 		file: file,
 		buf:  bufio.NewReadWriter(bufio.NewReader(file), bufio.NewWriter(file)),
 		conf: conf,
+		opt:  o,
 	}, nil
 }
 
@@ -63,7 +75,7 @@ func (ed *Editer) Close() error {
 		return err
 	}
 
-	Log.Printf("File %q edited", ed.file.Name())
+	ed.opt.log.Printf("File %q edited", ed.file.Name())
 	return nil
 }
 
@@ -74,8 +86,10 @@ func (ed *Editer) Append(b []byte) error {
 		return err
 	}
 
-	_, err = ed.file.Write(b)
-	return err
+	if _, err = ed.file.Write(b); err != nil {
+		return err
+	}
+	return ed.sync()
 }
 
 // AppendString is like Append, but writes the contents of string s rather than an array of bytes.
@@ -109,8 +123,10 @@ func (ed *Editer) Delete(begin, end int64) error {
 		return err
 	}
 
-	_, err = ed.file.Write(buf.Bytes())
-	return err
+	if _, err = ed.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return ed.sync()
 }
 
 // Comment inserts the comment character in lines that mach any regular expression in reLine.
@@ -346,6 +362,10 @@ func (ed *Editer) genReplaceAtLine(r []ReplacerAtLine, n int) error {
 }
 
 func (ed *Editer) rewrite(b []byte) error {
+	if ed.opt.atomic {
+		return ed.rewriteAtomic(b)
+	}
+
 	_, err := ed.file.Seek(0, io.SeekStart)
 	if err != nil {
 		return err
@@ -354,9 +374,47 @@ func (ed *Editer) rewrite(b []byte) error {
 		return err
 	}
 
-	_, err = ed.file.Write(b)
-	return err
-	//return ed.file.Sync()
+	if _, err = ed.file.Write(b); err != nil {
+		return err
+	}
+	return ed.sync()
+}
+
+// sync commits the changes to disk, as it is set by the option WithSync.
+func (ed *Editer) sync() error {
+	if ed.opt.sync >= SyncFile {
+		if err := ed.file.Sync(); err != nil {
+			return err
+		}
+	}
+	return ed.opt.syncDirOf(ed.file.Name())
+}
+
+// rewriteAtomic writes b into a new file which replaces the file edited, and
+// then it is opened.
+func (ed *Editer) rewriteAtomic(b []byte) error {
+	name := ed.file.Name()
+
+	err := ed.opt.writeFile(name, func(file *os.File) error {
+		_, err := file.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(name, os.O_RDWR, 0666)
+	if err != nil {
+		return err
+	}
+	if err = ed.file.Close(); err != nil {
+		file.Close()
+		return err
+	}
+
+	ed.file = file
+	ed.buf = bufio.NewReadWriter(bufio.NewReader(file), bufio.NewWriter(file))
+	return nil
 }
 
 // * * *
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 )
 
@@ -247,3 +248,33 @@ func TestEdit(t *testing.T) {
 		t.Errorf("HasPrefix: must not find %s", start)
 	}
 }
+
+func TestEditAtomic(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "foo")
+
+	if err := CreateString(filename, "foo = 1\nbar = 2\n"); err != nil {
+		t.Fatal(err)
+	}
+	ed, err := NewEdit(filename, nil, WithAtomic(true), WithSync(SyncDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ed.Replace([]Replacer{{"foo", "FOO"}}); err != nil {
+		t.Error(err)
+	}
+	if err = ed.Replace([]Replacer{{"bar", "BAR"}}); err != nil {
+		t.Error(err)
+	}
+	if err = ed.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "FOO = 1\nBAR = 2\n"; string(b) != want {
+		t.Errorf("got %q, want %q", b, want)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Filesystem represents the filesystem where a file is, and its mount.
+type Filesystem struct {
+	Type       string   // type of filesystem, like "ext4", "tmpfs", "nfs4" or "overlay"
+	Source     string   // mounted device or resource
+	MountPoint string   // directory where it is mounted
+	Options    []string // options of the mount, like "ro", "noexec" or "nosuid"
+
+	ReadOnly bool
+	NoExec   bool
+	NoSuid   bool
+
+	// Space in bytes.
+	Total uint64
+	Free  uint64
+	Avail uint64 // free space available to unprivileged users
+
+	Inodes     uint64
+	FreeInodes uint64
+}
+
+// HasOption reports whether the filesystem is mounted with the option opt.
+func (fs *Filesystem) HasOption(opt string) bool {
+	for _, v := range fs.Options {
+		if v == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnder reports whether the path is the directory dir or it is into it.
+// Both paths have to be clean.
+func isUnder(path, dir string) bool {
+	const sep = string(filepath.Separator)
+
+	if dir == sep || path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, sep)+sep)
+}
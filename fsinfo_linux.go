@@ -0,0 +1,144 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// fileMountinfo is the file with the mounts seen by the process.
+const fileMountinfo = "/proc/self/mountinfo"
+
+// flags got in: `man 2 statfs`
+const (
+	stRdonly = 0x0001 // mounted read-only
+	stNosuid = 0x0002 // setuid and setgid bits ignored
+	stNoexec = 0x0008 // execution of programs disallowed
+)
+
+// FSInfo returns the information about the filesystem where the named file
+// is, got through the system call statfs and the file /proc/self/mountinfo.
+func FSInfo(name string) (*Filesystem, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(name, &st); err != nil {
+		return nil, &os.PathError{Op: "statfs", Path: name, Err: err}
+	}
+
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(fileMountinfo)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fs, err := parseMountinfo(file, path)
+	if err != nil {
+		return nil, err
+	}
+	if fs == nil {
+		fs = &Filesystem{Type: fmt.Sprintf("0x%x", st.Type)}
+	}
+
+	size := uint64(st.Frsize)
+	if size == 0 {
+		size = uint64(st.Bsize)
+	}
+	fs.Total = uint64(st.Blocks) * size
+	fs.Free = uint64(st.Bfree) * size
+	fs.Avail = uint64(st.Bavail) * size
+	fs.Inodes = uint64(st.Files)
+	fs.FreeInodes = uint64(st.Ffree)
+
+	flags := uint64(st.Flags)
+	fs.ReadOnly = flags&stRdonly != 0 || fs.HasOption("ro")
+	fs.NoSuid = flags&stNosuid != 0 || fs.HasOption("nosuid")
+	fs.NoExec = flags&stNoexec != 0 || fs.HasOption("noexec")
+
+	return fs, nil
+}
+
+// parseMountinfo returns the mount, from the format of /proc/self/mountinfo,
+// which contains the absolute path, or nil if it is not found. The format is
+// described at `man 5 proc`:
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//
+// When there are several mounts at the same point, the last one is used since
+// it hides the rest.
+func parseMountinfo(r io.Reader, path string) (*Filesystem, error) {
+	var fs *Filesystem
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+
+		sep := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep == -1 || sep+2 >= len(fields) {
+			return nil, fmt.Errorf("invalid line at %s: %q", fileMountinfo, s.Text())
+		}
+
+		mountPoint := unescapeMount(fields[4])
+		if !isUnder(path, mountPoint) || fs != nil && len(mountPoint) < len(fs.MountPoint) {
+			continue
+		}
+
+		fs = &Filesystem{
+			Type:       fields[sep+1],
+			Source:     unescapeMount(fields[sep+2]),
+			MountPoint: mountPoint,
+			Options:    strings.Split(fields[5], ","),
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// unescapeMount returns the field of mountinfo without the characters escaped
+// in octal, like "\040" for the space.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMountinfo(t *testing.T) {
+	const mountinfo = `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+30 22 0:25 / /tmp rw,nosuid,nodev shared:5 - tmpfs tmpfs rw,size=1024k
+31 22 0:40 / /mnt/my\040disk ro,noexec - nfs4 server:/export rw
+32 30 0:26 / /tmp rw,noexec - tmpfs other rw
+`
+	for _, v := range []struct {
+		path string
+		want Filesystem
+	}{
+		{"/home/foo", Filesystem{Type: "ext4", Source: "/dev/sda1", MountPoint: "/",
+			Options: []string{"rw", "relatime"}}},
+		{"/tmpfoo", Filesystem{Type: "ext4", Source: "/dev/sda1", MountPoint: "/",
+			Options: []string{"rw", "relatime"}}},
+		{"/tmp/foo", Filesystem{Type: "tmpfs", Source: "other", MountPoint: "/tmp",
+			Options: []string{"rw", "noexec"}}},
+		{"/mnt/my disk", Filesystem{Type: "nfs4", Source: "server:/export", MountPoint: "/mnt/my disk",
+			Options: []string{"ro", "noexec"}}},
+	} {
+		fs, err := parseMountinfo(strings.NewReader(mountinfo), v.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fs == nil || !reflect.DeepEqual(*fs, v.want) {
+			t.Errorf("%s: got %+v, want %+v", v.path, fs, v.want)
+		}
+	}
+}
+
+func TestFSInfo(t *testing.T) {
+	dir := t.TempDir()
+
+	fs, err := FSInfo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs.Type == "" || fs.MountPoint == "" {
+		t.Errorf("got %+v", fs)
+	}
+	if fs.Total < fs.Free || fs.Free < fs.Avail || fs.Inodes < fs.FreeInodes {
+		t.Errorf("got space %d, %d, %d and inodes %d, %d",
+			fs.Total, fs.Free, fs.Avail, fs.Inodes, fs.FreeInodes)
+	}
+	if fs.ReadOnly {
+		t.Error("got read-only")
+	}
+
+	if fs, err = FSInfo("/proc/self"); err != nil {
+		t.Fatal(err)
+	}
+	if fs.Type != "proc" || fs.MountPoint != "/proc" {
+		t.Errorf("/proc: got type %q at %q", fs.Type, fs.MountPoint)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !linux
+// +build !linux
+
+package fileutil
+
+import "os"
+
+// FSInfo returns the information about the filesystem where the named file
+// is. In this system, it is not supported.
+func FSInfo(name string) (*Filesystem, error) {
+	return nil, &os.PathError{Op: "fsinfo", Path: name, Err: errNotSupported}
+}
@@ -6,7 +6,13 @@
 
 package fileutil
 
-import "os"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
 
 // flags got in: `man 2 stat`
 const (
@@ -21,20 +27,28 @@ const (
 	modeROthers = 00004 // others have read permission
 	modeWOthers = 00002 // others have write permission
 	modeXOthers = 00001 // others have execute permission
+
+	modeSetuid = 04000 // set-user-ID bit
+	modeSetgid = 02000 // set-group-ID bit
+	modeSticky = 01000 // sticky bit
 )
 
-type perm uint8
+// A Perm is a permission to read, write or execute a file.
+type Perm uint8
 
 // permissions
 const (
-	_ perm = iota
+	_ Perm = iota
 	R      // read
 	W      // write
 	X      // execute
 )
 
 // Info represents a wrapper about os.FileInfo to append some functions.
-type Info struct{ fi os.FileInfo }
+type Info struct {
+	name string
+	fi   os.FileInfo
+}
 
 // NewInfo returns a Info describing the named file.
 func NewInfo(name string) (Info, error) {
@@ -42,7 +56,7 @@ func NewInfo(name string) (Info, error) {
 	if err != nil {
 		return Info{}, err
 	}
-	return Info{i}, nil
+	return Info{name, i}, nil
 }
 
 // IsDir reports whether if it is a directory.
@@ -55,8 +69,83 @@ func (i Info) IsFile() bool {
 	return i.fi.Mode()&os.ModeType == 0
 }
 
+// IsNamedPipe reports whether it is a named pipe (FIFO).
+func (i Info) IsNamedPipe() bool {
+	return i.fi.Mode()&os.ModeNamedPipe != 0
+}
+
+// IsSocket reports whether it is a Unix domain socket.
+func (i Info) IsSocket() bool {
+	return i.fi.Mode()&os.ModeSocket != 0
+}
+
+// IsBlockDevice reports whether it is a block device.
+func (i Info) IsBlockDevice() bool {
+	mode := i.fi.Mode()
+	return mode&os.ModeDevice != 0 && mode&os.ModeCharDevice == 0
+}
+
+// IsCharDevice reports whether it is a character device.
+func (i Info) IsCharDevice() bool {
+	return i.fi.Mode()&os.ModeCharDevice != 0
+}
+
+// A FileType represents the type of a file.
+type FileType uint8
+
+// Types of files.
+const (
+	TypeRegular FileType = iota
+	TypeDir
+	TypeSymlink
+	TypeNamedPipe
+	TypeSocket
+	TypeBlockDevice
+	TypeCharDevice
+	TypeIrregular // a file of unknown type
+)
+
+var fileTypeNames = [...]string{
+	TypeRegular:     "regular file",
+	TypeDir:         "directory",
+	TypeSymlink:     "symbolic link",
+	TypeNamedPipe:   "named pipe",
+	TypeSocket:      "socket",
+	TypeBlockDevice: "block device",
+	TypeCharDevice:  "character device",
+	TypeIrregular:   "irregular file",
+}
+
+func (t FileType) String() string {
+	if int(t) < len(fileTypeNames) {
+		return fileTypeNames[t]
+	}
+	return "FileType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Type returns the type of the file.
+func (i Info) Type() FileType {
+	switch mode := i.fi.Mode(); {
+	case mode.IsRegular():
+		return TypeRegular
+	case mode.IsDir():
+		return TypeDir
+	case mode&os.ModeSymlink != 0:
+		return TypeSymlink
+	case mode&os.ModeNamedPipe != 0:
+		return TypeNamedPipe
+	case mode&os.ModeSocket != 0:
+		return TypeSocket
+	case mode&os.ModeCharDevice != 0:
+		return TypeCharDevice
+	case mode&os.ModeDevice != 0:
+		return TypeBlockDevice
+	}
+	return TypeIrregular
+}
+
 // OwnerHas reports whether the owner has all given permissions.
-func (i Info) OwnerHas(p ...perm) bool {
+func (i Info) OwnerHas(p ...Perm) bool {
 	mode := i.fi.Mode()
 
 	for _, v := range p {
@@ -79,7 +168,7 @@ func (i Info) OwnerHas(p ...perm) bool {
 }
 
 // GroupHas reports whether the group has all given permissions.
-func (i Info) GroupHas(p ...perm) bool {
+func (i Info) GroupHas(p ...Perm) bool {
 	mode := i.fi.Mode()
 
 	for _, v := range p {
@@ -102,7 +191,7 @@ func (i Info) GroupHas(p ...perm) bool {
 }
 
 // OthersHave reports whether the others have all given permissions.
-func (i Info) OthersHave(p ...perm) bool {
+func (i Info) OthersHave(p ...Perm) bool {
 	mode := i.fi.Mode()
 
 	for _, v := range p {
@@ -124,6 +213,116 @@ func (i Info) OthersHave(p ...perm) bool {
 	return true
 }
 
+// CanRead reports whether the process can read the file, according to its
+// effective user and group ids, its supplementary groups and the POSIX ACL
+// of the file (only at Linux; at other systems, it is checked the owner
+// permission).
+func (i Info) CanRead() bool { return i.can(R) }
+
+// CanWrite reports whether the process can write the file, like at CanRead.
+// It is not checked whether the filesystem is mounted read-only.
+func (i Info) CanWrite() bool { return i.can(W) }
+
+// CanExec reports whether the process can execute the file, or search into
+// the directory, like at CanRead.
+func (i Info) CanExec() bool { return i.can(X) }
+
+// IsSetuid reports whether the file has the set-user-ID bit.
+func (i Info) IsSetuid() bool {
+	return i.fi.Mode()&os.ModeSetuid != 0
+}
+
+// IsSetgid reports whether the file has the set-group-ID bit.
+func (i Info) IsSetgid() bool {
+	return i.fi.Mode()&os.ModeSetgid != 0
+}
+
+// IsSticky reports whether the file has the sticky bit.
+func (i Info) IsSticky() bool {
+	return i.fi.Mode()&os.ModeSticky != 0
+}
+
+// Symbolic returns the mode of the file in symbolic form, like it is shown by
+// the command `ls -l`, i.e. "-rwxr-xr-x" or "drwxrwxrwt".
+func (i Info) Symbolic() string {
+	bits := toUnixMode(i.fi.Mode())
+	buf := []byte("----------")
+	buf[0] = "-dlpsbc?"[i.Type()]
+
+	for j, c := range "rwxrwxrwx" {
+		if bits&(1<<uint(8-j)) != 0 {
+			buf[j+1] = byte(c)
+		}
+	}
+
+	// The special modes are shown at the execute permission, in lowercase if
+	// that permission is set.
+	special := func(pos int, set bool, c byte) {
+		if set {
+			if buf[pos] == 'x' {
+				buf[pos] = c
+			} else {
+				buf[pos] = c - 'a' + 'A'
+			}
+		}
+	}
+	special(3, bits&modeSetuid != 0, 's')
+	special(6, bits&modeSetgid != 0, 's')
+	special(9, bits&modeSticky != 0, 't')
+
+	return string(buf)
+}
+
+// Octal returns the permissions and special modes of the file in octal form,
+// i.e. "0755" or "1777".
+func (i Info) Octal() string {
+	return fmt.Sprintf("%04o", toUnixMode(i.fi.Mode()))
+}
+
+// An Ident represents the numeric id and the name of a user or group.
+// The name is empty if the id is not found in the system.
+type Ident struct {
+	ID   int
+	Name string
+}
+
+// Owner returns the user who owns the file. The ID is -1 if it is not known in
+// this system.
+func (i Info) Owner() (Ident, error) {
+	uid, _ := i.ownerIDs()
+	if uid == -1 {
+		return Ident{ID: -1}, nil
+	}
+
+	u, err := user.LookupId(strconv.Itoa(uid))
+	if err != nil {
+		var errUnknown user.UnknownUserIdError
+		if errors.As(err, &errUnknown) {
+			return Ident{ID: uid}, nil
+		}
+		return Ident{ID: uid}, err
+	}
+	return Ident{uid, u.Username}, nil
+}
+
+// Group returns the group which owns the file, like at Owner.
+func (i Info) Group() (Ident, error) {
+	_, gid := i.ownerIDs()
+	if gid == -1 {
+		return Ident{ID: -1}, nil
+	}
+
+	g, err := user.LookupGroupId(strconv.Itoa(gid))
+	if err != nil {
+		var errUnknown user.UnknownGroupIdError
+		if errors.As(err, &errUnknown) {
+			return Ident{ID: gid}, nil
+		}
+		return Ident{ID: gid}, err
+	}
+	return Ident{gid, g.Name}, nil
+}
+
 // * * *
 
 // IsDir reports whether if the named file is a directory.
@@ -145,7 +344,7 @@ func IsFile(name string) (bool, error) {
 }
 
 // OwnerHas reports whether the named file has all given permissions for the owner.
-func OwnerHas(name string, p ...perm) (bool, error) {
+func OwnerHas(name string, p ...Perm) (bool, error) {
 	i, err := NewInfo(name)
 	if err != nil {
 		return false, err
@@ -154,7 +353,7 @@ func OwnerHas(name string, p ...perm) (bool, error) {
 }
 
 // GroupHas reports whether the named file has all given permissions for the group.
-func GroupHas(name string, p ...perm) (bool, error) {
+func GroupHas(name string, p ...Perm) (bool, error) {
 	i, err := NewInfo(name)
 	if err != nil {
 		return false, err
@@ -163,10 +362,46 @@ func GroupHas(name string, p ...perm) (bool, error) {
 }
 
 // OthersHave reports whether the named file have all given permissions for the others.
-func OthersHave(name string, p ...perm) (bool, error) {
+func OthersHave(name string, p ...Perm) (bool, error) {
 	i, err := NewInfo(name)
 	if err != nil {
 		return false, err
 	}
 	return i.OthersHave(p...), nil
 }
+
+// SetSetuid sets or clears the set-user-ID bit of the named file.
+func SetSetuid(name string, ok bool) error { return setModeBit(name, os.ModeSetuid, ok) }
+
+// SetSetgid sets or clears the set-group-ID bit of the named file.
+func SetSetgid(name string, ok bool) error { return setModeBit(name, os.ModeSetgid, ok) }
+
+// SetSticky sets or clears the sticky bit of the named file.
+func SetSticky(name string, ok bool) error { return setModeBit(name, os.ModeSticky, ok) }
+
+// setModeBit sets or clears the bit of the named file.
+func setModeBit(name string, bit os.FileMode, ok bool) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+
+	mode := info.Mode() & modeMask
+	if ok {
+		mode |= bit
+	} else {
+		mode &^= bit
+	}
+	return os.Chmod(name, mode)
+}
+
+// CanRead reports whether the process can read the named file.
+// At Linux, it is used the system call faccessat, with the effective ids.
+func CanRead(name string) (bool, error) { return canAccess(name, R) }
+
+// CanWrite reports whether the process can write the named file, like at CanRead.
+func CanWrite(name string) (bool, error) { return canAccess(name, W) }
+
+// CanExec reports whether the process can execute the named file, or search
+// into the directory, like at CanRead.
+func CanExec(name string) (bool, error) { return canAccess(name, X) }
@@ -0,0 +1,65 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"net"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestInfoType(t *testing.T) {
+	dir := t.TempDir()
+	fifo := filepath.Join(dir, "fifo")
+	sock := filepath.Join(dir, "sock")
+
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for _, v := range []struct {
+		name     string
+		typ      FileType
+		symbolic byte
+	}{
+		{dir, TypeDir, 'd'},
+		{fifo, TypeNamedPipe, 'p'},
+		{sock, TypeSocket, 's'},
+		{"/dev/null", TypeCharDevice, 'c'},
+	} {
+		info, err := NewLinkInfo(v.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Type() != v.typ {
+			t.Errorf("%s: got type %s, want %s", v.name, info.Type(), v.typ)
+		}
+		if info.Symbolic()[0] != v.symbolic {
+			t.Errorf("%s: got mode %s", v.name, info.Symbolic())
+		}
+	}
+
+	info, err := NewInfo("/dev/null")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsCharDevice() || info.IsBlockDevice() || info.IsNamedPipe() || info.IsSocket() {
+		t.Error("/dev/null: expected to be only a character device")
+	}
+	if major, minor := info.DeviceNumber(); major != 1 || minor != 3 {
+		t.Errorf("/dev/null: got device %d:%d, want 1:3", major, minor)
+	}
+}
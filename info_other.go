@@ -0,0 +1,28 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !solaris
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd,!solaris
+
+package fileutil
+
+// ownerIDs returns -1 since the ids of the owner are not known in this system.
+func (i Info) ownerIDs() (uid, gid int) { return -1, -1 }
+
+// Inode returns 0 since it is not known in this system.
+func (i Info) Inode() uint64 { return 0 }
+
+// Device returns 0 since it is not known in this system.
+func (i Info) Device() uint64 { return 0 }
+
+// Links returns 1 since the number of hard links is not known in this system.
+func (i Info) Links() uint64 { return 1 }
+
+// Blocks returns 0 since it is not known in this system.
+func (i Info) Blocks() int64 { return 0 }
+
+// DeviceNumber returns 0 since it is not known in this system.
+func (i Info) DeviceNumber() (major, minor uint32) { return 0, 0 }
@@ -0,0 +1,101 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd netbsd openbsd solaris
+
+package fileutil
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// stat returns the status of the file given by the system.
+func (i Info) stat() *syscall.Stat_t {
+	st, _ := i.fi.Sys().(*syscall.Stat_t)
+	return st
+}
+
+// ownerIDs returns the numeric user and group ids of the file, or -1 if they
+// are not known.
+func (i Info) ownerIDs() (uid, gid int) {
+	st := i.stat()
+	if st == nil {
+		return -1, -1
+	}
+	return int(st.Uid), int(st.Gid)
+}
+
+// Inode returns the inode number of the file.
+func (i Info) Inode() uint64 {
+	if st := i.stat(); st != nil {
+		return uint64(st.Ino)
+	}
+	return 0
+}
+
+// Device returns the id of the device which contains the file.
+func (i Info) Device() uint64 {
+	if st := i.stat(); st != nil {
+		return devToUint(int64(st.Dev))
+	}
+	return 0
+}
+
+// Links returns the number of hard links to the file.
+func (i Info) Links() uint64 {
+	if st := i.stat(); st != nil {
+		return uint64(st.Nlink)
+	}
+	return 0
+}
+
+// Blocks returns the number of blocks of 512 bytes allocated to the file.
+// It can be lesser than the size for sparse files.
+func (i Info) Blocks() int64 {
+	if st := i.stat(); st != nil {
+		return int64(st.Blocks)
+	}
+	return 0
+}
+
+// DeviceNumber returns the major and minor numbers of the device, if the file
+// is a block or character device.
+func (i Info) DeviceNumber() (major, minor uint32) {
+	st := i.stat()
+	if st == nil || i.fi.Mode()&os.ModeDevice == 0 {
+		return 0, 0
+	}
+
+	// The encodings used by the macros 'major' and 'minor' of every system.
+	dev := devToUint(int64(st.Rdev))
+	switch runtime.GOOS {
+	case "darwin", "ios":
+		return uint32(dev >> 24 & 0xff), uint32(dev & 0xffffff)
+	case "dragonfly":
+		return uint32(dev >> 8 & 0xff), uint32(dev & 0xffff00ff)
+	case "freebsd":
+		return uint32(dev>>32&0xffffff00 | dev>>8&0xff), uint32(dev>>24&0xff00 | dev&0xffff00ff)
+	case "netbsd":
+		return uint32(dev & 0x000fff00 >> 8), uint32(dev&0xff | dev&0xfff00000>>12)
+	case "openbsd":
+		return uint32(dev & 0xff00 >> 8), uint32(dev&0xff | dev&0xffff0000>>8)
+	}
+	// Solaris and illumos, at 64 bits.
+	return uint32(dev >> 32), uint32(dev & 0xffffffff)
+}
+
+// devToUint returns the id of a device as unsigned. At the systems where it is
+// a signed integer of 32 bits, it is not extended the sign.
+func devToUint(dev int64) uint64 {
+	switch runtime.GOOS {
+	case "darwin", "ios", "openbsd":
+		return uint64(dev) & 0xffffffff
+	}
+	return uint64(dev)
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd || solaris
+// +build linux darwin dragonfly freebsd netbsd openbsd solaris
+
+package fileutil
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+)
+
+func TestInfoOwner(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "foo")
+
+	if err := os.WriteFile(filename, []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(filename, filepath.Join(dir, "bar")); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := NewInfo(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner, err := info.Owner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner.ID != os.Getuid() {
+		t.Errorf("Owner: got uid %d, want %d", owner.ID, os.Getuid())
+	}
+	if u, err := user.Current(); err == nil && owner.Name != u.Username {
+		t.Errorf("Owner: got name %q, want %q", owner.Name, u.Username)
+	}
+
+	group, err := info.Group()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.ID != os.Getgid() {
+		t.Errorf("Group: got gid %d, want %d", group.ID, os.Getgid())
+	}
+
+	if info.Links() != 2 {
+		t.Errorf("Links: got %d, want 2", info.Links())
+	}
+	if info.Inode() == 0 {
+		t.Error("Inode: got 0")
+	}
+	dirInfo, err := NewInfo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Device() != dirInfo.Device() {
+		t.Errorf("Device: got %d, want %d", info.Device(), dirInfo.Device())
+	}
+}
@@ -7,23 +7,53 @@
 package fileutil
 
 import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"hash"
 	"io"
 	"os"
 )
 
 // Copy copies file from 'source' to file in 'dest' preserving the mode attributes.
-// It makes a backup if the global variable 'DoBackup' is set to true.
-func Copy(source, dest string) (err error) {
-	// Don't backup files of backup.
-	if DoBackup && dest[len(dest)-1] != '~' {
-		if err = Backup(dest); err != nil {
-			return
+// The metadata to preserve can be set with the option WithPreserve.
+// It makes a backup if the global variable 'DoBackup' is set to true, unless
+// it is set by the option WithBackup.
+func Copy(source, dest string, opt ...Option) (err error) {
+	o := newOptions(opt)
+
+	_, err = o.copy(source, dest)
+	return err
+}
+
+// copy copies the file 'source' to 'dest', like copyFileSum, making before a
+// backup of 'dest' if it is set in o.
+func (o *options) copy(source, dest string) ([]byte, error) {
+	// Don't backup files of backup, nor partial copies.
+	if o.backup && !o.resume && dest[len(dest)-1] != '~' {
+		if _, err := o.backupFile(dest); err != nil {
+			return nil, err
 		}
 	}
 
+	return o.copyFileSum(source, dest)
+}
+
+// copyFile copies file from 'source' to file in 'dest'.
+// It is copied the metadata of the source file set in o.preserve (see copyMeta);
+// anyway, the permission bits are used when the file is created.
+func (o *options) copyFile(source, dest string) error {
+	_, err := o.copyFileSum(source, dest)
+	return err
+}
+
+// copyFileSum is like copyFile, but it also returns the checksum of the source
+// file computed with o.newHash, if it is set. If o.verify is true, it is
+// checked the checksum of the destination file.
+func (o *options) copyFileSum(source, dest string) (sum []byte, err error) {
 	srcFile, err := os.Open(source)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		if err2 := srcFile.Close(); err2 != nil && err == nil {
@@ -33,85 +63,122 @@ func Copy(source, dest string) (err error) {
 
 	srcInfo, err := os.Stat(source)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	dstFile, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, srcInfo.Mode().Perm())
-	if err != nil {
-		return err
+	if !o.permSet {
+		o2 := *o
+		o2.perm = srcInfo.Mode().Perm()
+		o = &o2
 	}
 
-	_, err = io.Copy(dstFile, srcFile)
-	err2 := dstFile.Close()
-	if err2 != nil && err == nil {
-		err = err2
+	var h hash.Hash
+	if o.newHash != nil {
+		h = o.newHash()
+	} else if o.verify {
+		h = sha256.New()
+	}
+
+	_, err = os.Lstat(dest)
+	existed := err == nil
+
+	resumed := false
+	if o.resume {
+		if resumed, err = o.resumeCopy(srcFile, srcInfo, dest, h); err != nil {
+			return nil, err
+		}
+	}
+	if !resumed {
+		err = o.writeFile(dest, func(dstFile *os.File) error {
+			return o.copyContent(dstFile, srcFile, srcInfo, h)
+		})
 	}
 	if err != nil {
-		return err
+		// Don't leave a file partially copied when the copy is cancelled,
+		// unless it can be resumed. An existing file is never removed.
+		var errExist *ExistError
+		if !o.atomic && !o.resume && !existed && o.ctx != nil && o.ctx.Err() != nil &&
+			!errors.As(err, &errExist) {
+			if err2 := os.Remove(dest); err2 != nil {
+				o.log.Printf("File %q not removed: %s", dest, err2)
+			}
+		}
+		return nil, err
+	}
+	if h != nil {
+		sum = h.Sum(nil)
 	}
 
-	Log.Printf("File %q copied at %q", source, dest)
-	return nil
+	if o.verify {
+		h.Reset()
+		if err = o.verifySum(dest, h, sum); err != nil {
+			return nil, err
+		}
+	}
+	if o.preserve != 0 {
+		if err = o.copyMeta(source, dest, srcInfo); err != nil {
+			return nil, err
+		}
+	}
+
+	o.log.Printf("File %q copied at %q", source, dest)
+	return sum, nil
 }
 
 // Create creates a new file with b bytes.
-func Create(filename string, b []byte) (err error) {
-	file, err := os.Create(filename)
-	if err != nil {
+// By default, an existing file is truncated; use the option WithExclusive to
+// fail instead. The permissions are 0666 (before of the umask), unless it is
+// set by the option WithPerm.
+func Create(filename string, b []byte, opt ...Option) (err error) {
+	o := newOptions(opt)
+
+	err = o.writeFile(filename, func(file *os.File) error {
+		_, err := file.Write(b)
 		return err
-	}
-
-	_, err = file.Write(b)
-	err2 := file.Close()
-	if err2 != nil && err == nil {
-		err = err2
-	}
+	})
 	if err != nil {
 		return err
 	}
 
-	Log.Printf("File %q created", filename)
+	o.log.Printf("File %q created", filename)
 	return nil
 }
 
 // CreateString is like Create, but writes the contents of string s rather than
 // an array of bytes.
-func CreateString(filename, s string) error {
-	return Create(filename, []byte(s))
+func CreateString(filename, s string, opt ...Option) error {
+	return Create(filename, []byte(s), opt...)
 }
 
 // Overwrite truncates the named file to zero and writes len(b) bytes.
-// It makes a backup if the global variable 'DoBackup' is set to true.
+// It makes a backup if the global variable 'DoBackup' is set to true, unless
+// it is set by the option WithBackup.
 // It returns an error, if any.
-func Overwrite(filename string, b []byte) (err error) {
-	if DoBackup {
-		if err = Backup(filename); err != nil {
+func Overwrite(filename string, b []byte, opt ...Option) (err error) {
+	o := newOptions(opt)
+
+	if o.backup {
+		if _, err = o.backupFile(filename); err != nil {
 			return err
 		}
 	}
 
-	file, err := os.Create(filename)
-	if err != nil {
+	err = o.writeFile(filename, func(file *os.File) error {
+		_, err := file.Write(b)
 		return err
-	}
-
-	_, err = file.Write(b)
-	err2 := file.Close()
-	if err2 != nil && err == nil {
-		err = err2
-	}
+	})
 	if err != nil {
 		return err
 	}
 
-	Log.Printf("File %q overwritted", filename)
+	o.log.Printf("File %q overwritted", filename)
 	return nil
 }
 
 // OverwriteString is like Overwrite, but writes the contents of string s rather
 // than an array of bytes.
-func OverwriteString(filename, s string) error {
-	return Overwrite(filename, []byte(s))
+func OverwriteString(filename, s string, opt ...Option) error {
+	return Overwrite(filename, []byte(s), opt...)
 }
 
 // == Utility
@@ -123,34 +190,24 @@ const prefixTemp = "tmp-" // Prefix to add to temporary files.
 // If prefix is the empty string, uses the default value prefixTemp.
 // Returns the temporary file name.
 func CopytoTemp(source, prefix string) (tmpFile string, err error) {
-	if prefix == "" {
-		prefix = prefixTemp
-	}
+	return CopytoTempContext(context.Background(), source, prefix)
+}
 
-	src, err := os.Open(source)
-	if err != nil {
-		return "", err
+// copyContent copies the content of src, described by info, to dst, writing it
+// also in h, if it is not nil.
+func (o *options) copyContent(dst, src *os.File, info os.FileInfo, h hash.Hash) error {
+	var r io.Reader = src
+	if h != nil {
+		r = io.TeeReader(src, h)
 	}
-	defer func() {
-		if err2 := src.Close(); err2 != nil && err == nil {
-			err = err2
-		}
-	}()
 
-	dest, err := os.CreateTemp("", prefix)
-	if err != nil {
-		return "", err
+	if o.ctx != nil && o.ctx.Done() != nil || o.progress != nil || o.rateLimit > 0 {
+		return o.copyContext(dst, r, 0, info.Size())
 	}
-	defer func() {
-		if err2 := dest.Close(); err2 != nil && err == nil {
-			err = err2
-		}
-	}()
-
-	if _, err = io.Copy(dest, src); err != nil {
-		return "", err
+	if h == nil {
+		return copyData(dst, src, info, o.method)
 	}
 
-	Log.Printf("File %q copied at %q", source, dest.Name())
-	return dest.Name(), nil
+	_, err := io.Copy(dst, r)
+	return err
 }
@@ -7,10 +7,13 @@
 package fileutil
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBackupSuffix(t *testing.T) {
@@ -66,3 +69,152 @@ func checkCopytoTemp(filename, prefix string, t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestCopyOptions(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "dest")
+
+	if err := Copy(FILENAME, dest, WithAtomic(true), WithPerm(0600)); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("got mode %v, want %v", fi.Mode().Perm(), os.FileMode(0600))
+	}
+
+	if err = Copy(FILENAME, dest, WithBackup(false)); err != nil {
+		t.Fatal(err)
+	}
+	if err = OverwriteString(dest, "foo", WithBackup(false), WithAtomic(true), WithSync(SyncDir)); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("got files %q, want only %q", files, dest)
+	}
+
+	// The mode of the file is kept at replacing it.
+	if fi, err = os.Stat(dest); err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("got mode %v, want %v", fi.Mode().Perm(), os.FileMode(0600))
+	}
+}
+
+func TestCopyPreserve(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	dest := filepath.Join(dir, "dest")
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if err := os.WriteFile(source, []byte("foo"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dest, []byte("bar"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(source, 0750|os.ModeSetgid); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(source, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Copy(source, dest, WithBackup(false), WithPreserve(PreserveMode|PreserveTimes)); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 0750 | os.ModeSetgid; fi.Mode()&modeMask != want {
+		t.Errorf("got mode %v, want %v", fi.Mode()&modeMask, want)
+	}
+	if !fi.ModTime().Equal(modTime) {
+		t.Errorf("got time %v, want %v", fi.ModTime(), modTime)
+	}
+}
+
+func TestCreateExclusive(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "sub", "foo")
+
+	err := CreateString(filename, "foo", WithExclusive(true), WithMkdirAll(0755), WithPerm(0600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("got mode %v, want %v", fi.Mode().Perm(), os.FileMode(0600))
+	}
+
+	for _, atomic := range []bool{false, true} {
+		err = CreateString(filename, "bar", WithExclusive(true), WithAtomic(atomic))
+
+		var errExist *ExistError
+		if !errors.As(err, &errExist) || !errors.Is(err, os.ErrExist) {
+			t.Errorf("atomic %v: got error %v, want *ExistError", atomic, err)
+		}
+	}
+	if b, _ := os.ReadFile(filename); string(b) != "foo" {
+		t.Errorf("got %q, want %q", b, "foo")
+	}
+	if files, _ := filepath.Glob(filename + "*"); len(files) != 1 {
+		t.Errorf("got files %q, want only %q", files, filename)
+	}
+}
+
+func TestWriteAtomicLink(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	target := filepath.Join(dir, "target")
+	link := filepath.Join(dir, "link")
+
+	if err := os.WriteFile(source, []byte("baz"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("target", link); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file pointed by the link is replaced, like at a write not atomic.
+	write := []struct {
+		want string
+		fn   func() error
+	}{
+		{"bar", func() error {
+			return OverwriteString(link, "bar", WithBackup(false), WithAtomic(true))
+		}},
+		{"baz", func() error {
+			return CopyContext(context.Background(), source, link, WithBackup(false))
+		}},
+	}
+	for _, v := range write {
+		if err := v.fn(); err != nil {
+			t.Fatal(err)
+		}
+		if fi, err := os.Lstat(link); err != nil {
+			t.Fatal(err)
+		} else if fi.Mode()&os.ModeSymlink == 0 {
+			t.Fatal("symbolic link replaced")
+		}
+		if b, _ := os.ReadFile(target); string(b) != v.want {
+			t.Errorf("got %q, want %q", b, v.want)
+		}
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 3 {
+		t.Errorf("got files %q, want 3", files)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxLinks is the maximum number of symbolic links followed at resolving a
+// link; it is the limit used by Linux.
+const maxLinks = 40
+
+// NewLinkInfo returns a Info describing the named file. If it is a symbolic
+// link, it describes the link, without following it.
+func NewLinkInfo(name string) (Info, error) {
+	i, err := os.Lstat(name)
+	if err != nil {
+		return Info{}, err
+	}
+	return Info{name, i}, nil
+}
+
+// IsSymlink reports whether it is a symbolic link.
+// It is only true for a Info got by NewLinkInfo.
+func (i Info) IsSymlink() bool {
+	return i.fi.Mode()&os.ModeSymlink != 0
+}
+
+// LinkTarget returns the destination of the symbolic link, as it is stored.
+func (i Info) LinkTarget() (string, error) {
+	return os.Readlink(i.name)
+}
+
+// IsDangling reports whether it is a symbolic link whose destination does not
+// exist.
+func (i Info) IsDangling() bool {
+	if !i.IsSymlink() {
+		return false
+	}
+	_, err := os.Stat(i.name)
+	return os.IsNotExist(err)
+}
+
+// LinkChain returns the files in the chain of symbolic links, like at
+// ResolveLink.
+func (i Info) LinkChain() ([]string, error) {
+	return ResolveLink(i.name)
+}
+
+// * * *
+
+// IsSymlink reports whether the named file is a symbolic link.
+func IsSymlink(name string) (bool, error) {
+	i, err := NewLinkInfo(name)
+	if err != nil {
+		return false, err
+	}
+	return i.IsSymlink(), nil
+}
+
+// ResolveLink follows the chain of symbolic links starting at the named file,
+// returning the path of every file in the chain; the last one is the final
+// destination. The relative links are resolved from the directory of the link.
+//
+// If a link is dangling, it is returned the chain until the destination which
+// does not exist, with the error. If there is a loop, it is returned an error.
+func ResolveLink(name string) ([]string, error) {
+	chain := []string{name}
+	seen := map[string]bool{filepath.Clean(name): true}
+
+	for {
+		cur := chain[len(chain)-1]
+
+		fi, err := os.Lstat(cur)
+		if err != nil {
+			return chain, err
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			return chain, nil
+		}
+
+		link, err := os.Readlink(cur)
+		if err != nil {
+			return chain, err
+		}
+		if !filepath.IsAbs(link) {
+			link = filepath.Dir(cur) + string(filepath.Separator) + link
+		}
+		link = cleanLink(link)
+
+		if seen[link] || len(chain) > maxLinks {
+			return chain, &os.PathError{Op: "resolvelink", Path: name, Err: errLinkLoop}
+		}
+		seen[link] = true
+		chain = append(chain, link)
+	}
+}
+
+// cleanLink returns the path of a link destination without the elements "..",
+// which are resolved by the system after of following the links in the path
+// before them, so that part is not cleaned lexically. If it can not be
+// resolved, it is returned the path cleaned.
+func cleanLink(path string) string {
+	elems := strings.Split(path, string(filepath.Separator))
+
+	last := -1
+	for i, e := range elems {
+		if e == ".." {
+			last = i
+		}
+	}
+	if last == -1 {
+		return filepath.Clean(path)
+	}
+
+	dir, err := filepath.EvalSymlinks(strings.Join(elems[:last+1], string(filepath.Separator)))
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(append([]string{dir}, elems[last+1:]...)...)
+}
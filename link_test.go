@@ -0,0 +1,122 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLinkInfo(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	link1 := filepath.Join(dir, "link1")
+	link2 := filepath.Join(dir, "link2")
+	dangling := filepath.Join(dir, "dangling")
+	loop1 := filepath.Join(dir, "loop1")
+	loop2 := filepath.Join(dir, "loop2")
+
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range [][2]string{
+		{"file", link1},
+		{link1, link2},
+		{"missing", dangling},
+		{"loop2", loop1},
+		{"loop1", loop2},
+	} {
+		if err := os.Symlink(v[0], v[1]); err != nil {
+			t.Skip(err)
+		}
+	}
+
+	info, err := NewLinkInfo(link2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsSymlink() || info.IsFile() || info.IsDangling() {
+		t.Errorf("got symlink %v, file %v, dangling %v; want true, false, false",
+			info.IsSymlink(), info.IsFile(), info.IsDangling())
+	}
+	if target, err := info.LinkTarget(); err != nil || target != link1 {
+		t.Errorf("LinkTarget: got %q, %v; want %q", target, err, link1)
+	}
+
+	chain, err := info.LinkChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{link2, link1, file}; !reflect.DeepEqual(chain, want) {
+		t.Errorf("LinkChain: got %q, want %q", chain, want)
+	}
+
+	if info, err = NewInfo(link2); err != nil {
+		t.Fatal(err)
+	}
+	if info.IsSymlink() || !info.IsFile() {
+		t.Error("NewInfo: expected to follow the link")
+	}
+
+	if info, err = NewLinkInfo(dangling); err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsDangling() {
+		t.Error("IsDangling: got false")
+	}
+	if _, err = ResolveLink(dangling); !os.IsNotExist(err) {
+		t.Errorf("ResolveLink: got %v, want not exist", err)
+	}
+
+	if _, err = ResolveLink(loop1); !errors.Is(err, errLinkLoop) {
+		t.Errorf("ResolveLink: got %v, want loop", err)
+	}
+}
+
+func TestResolveLinkParent(t *testing.T) {
+	dir := t.TempDir()
+	alias := filepath.Join(dir, "alias")
+	target := filepath.Join(dir, "real", "target")
+	decoy := filepath.Join(dir, "target")
+
+	if err := os.MkdirAll(filepath.Join(dir, "real", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{target, decoy} {
+		if err := os.WriteFile(v, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range [][2]string{
+		{filepath.Join("real", "sub"), alias},
+		{filepath.Join("..", "target"), filepath.Join(dir, "real", "sub", "link")},
+	} {
+		if err := os.Symlink(v[0], v[1]); err != nil {
+			t.Skip(err)
+		}
+	}
+
+	// The ".." is resolved from "real/sub", where the link is.
+	want, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Remove(decoy); err != nil {
+		t.Fatal(err)
+	}
+
+	chain, err := ResolveLink(filepath.Join(alias, "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := chain[len(chain)-1]; got != want {
+		t.Errorf("got destination %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import "os"
+
+// A Preserve value is a set of flags (or 0) with the metadata to preserve at
+// copying a file.
+type Preserve uint
+
+// Metadata to preserve at copying a file.
+const (
+	PreserveMode  Preserve = 1 << iota // Permissions, setuid, setgid and sticky bits.
+	PreserveOwner                      // Owner and group.
+	PreserveTimes                      // Access and modification times.
+	PreserveXattr                      // Extended attributes, like the SELinux labels.
+	PreserveACL                        // POSIX ACLs.
+
+	PreserveAll = PreserveMode | PreserveOwner | PreserveTimes | PreserveXattr | PreserveACL
+)
+
+// WithPreserve sets the metadata of the source file to preserve at copying.
+// The mode is applied even when the destination file already exists.
+//
+// By default, Copy only uses the permissions of the source file at creating
+// the destination file, CopyDir preserves the mode and times, and Move
+// preserves all metadata.
+func WithPreserve(p Preserve) Option {
+	return func(o *options) {
+		o.preserve, o.preserveSet = p, true
+	}
+}
+
+// modeMask is the mask of the mode bits set by chmod.
+const modeMask = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
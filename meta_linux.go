@@ -0,0 +1,143 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// copyMeta copies the metadata of the file src, described by fi, to dst, as
+// it is set in o.preserve: owner and group, extended attributes (like the
+// SELinux labels), POSIX ACLs, mode, and access and modification times.
+//
+// Whatever the process has not privileges to do is skipped.
+func (o *options) copyMeta(src, dst string, fi os.FileInfo) error {
+	if o.preserve&PreserveOwner != 0 {
+		if err := o.copyOwner(dst, fi); err != nil {
+			return err
+		}
+	}
+	if o.preserve&(PreserveXattr|PreserveACL) != 0 {
+		if err := o.copyXattrs(src, dst); err != nil {
+			return err
+		}
+	}
+
+	// The mode is set after of the owner since 'chown' clears the setuid and
+	// setgid bits.
+	if o.preserve&PreserveMode != 0 {
+		if err := os.Chmod(dst, fi.Mode()&modeMask); err != nil {
+			return err
+		}
+	}
+
+	if o.preserve&PreserveTimes != 0 {
+		return os.Chtimes(dst, accessTime(fi), fi.ModTime())
+	}
+	return nil
+}
+
+// copyOwner sets the owner and group of the file described by fi to dst,
+// without following symbolic links. It is skipped without privileges.
+func (o *options) copyOwner(dst string, fi os.FileInfo) error {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	if err := os.Lchown(dst, int(st.Uid), int(st.Gid)); err != nil {
+		if !isNotPermitted(err) {
+			return err
+		}
+		o.log.Printf("Owner of %q not preserved: %s", dst, err)
+	}
+	return nil
+}
+
+// sameOwner reports whether both files have the same owner and group.
+func sameOwner(fi1, fi2 os.FileInfo) bool {
+	st1, ok1 := fi1.Sys().(*syscall.Stat_t)
+	st2, ok2 := fi2.Sys().(*syscall.Stat_t)
+	if !ok1 || !ok2 {
+		return ok1 == ok2
+	}
+	return st1.Uid == st2.Uid && st1.Gid == st2.Gid
+}
+
+// Extended attributes used to store the POSIX ACLs.
+const (
+	xattrACLAccess  = "system.posix_acl_access"
+	xattrACLDefault = "system.posix_acl_default"
+)
+
+// copyXattrs copies the extended attributes of the file src to dst, the ACLs
+// and the rest of attributes as they are set in o.preserve.
+func (o *options) copyXattrs(src, dst string) error {
+	names, err := ListXattr(src)
+	if err != nil {
+		if isNotSupported(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, name := range names {
+		isACL := name == xattrACLAccess || name == xattrACLDefault
+		if isACL && o.preserve&PreserveACL == 0 || !isACL && o.preserve&PreserveXattr == 0 {
+			continue
+		}
+
+		value, err := GetXattr(src, name)
+		if err != nil {
+			if isNotSupported(err) || errors.Is(err, syscall.ENODATA) {
+				continue
+			}
+			return err
+		}
+
+		if err = SetXattr(dst, name, value); err != nil {
+			if !isNotPermitted(err) && !isNotSupported(err) {
+				return err
+			}
+			o.log.Printf("Extended attribute %q of %q not preserved: %s", name, dst, err)
+		}
+	}
+	return nil
+}
+
+// accessTime returns the time of the last access to the file.
+func accessTime(fi os.FileInfo) time.Time {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(st.Atim.Unix())
+	}
+	return fi.ModTime()
+}
+
+// changeTime returns the time of the last change of the file status.
+func changeTime(fi os.FileInfo) time.Time {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(st.Ctim.Unix())
+	}
+	return fi.ModTime()
+}
+
+// isNotPermitted reports whether the error is due to the lack of privileges.
+func isNotPermitted(err error) bool {
+	return errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EACCES)
+}
+
+// isNotSupported reports whether the error is due to an operation not
+// supported by the filesystem.
+func isNotSupported(err error) bool {
+	return errors.Is(err, syscall.ENOTSUP) || errors.Is(err, syscall.EOPNOTSUPP)
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !linux
+// +build !linux
+
+package fileutil
+
+import (
+	"os"
+	"time"
+)
+
+// copyMeta copies the metadata of the file src, described by fi, to dst, as
+// it is set in o.preserve. In this system, they are only copied the mode and
+// the modification time.
+func (o *options) copyMeta(src, dst string, fi os.FileInfo) error {
+	if o.preserve&PreserveMode != 0 {
+		if err := os.Chmod(dst, fi.Mode()&modeMask); err != nil {
+			return err
+		}
+	}
+	if o.preserve&PreserveTimes != 0 {
+		return os.Chtimes(dst, fi.ModTime(), fi.ModTime())
+	}
+	return nil
+}
+
+// copyOwner sets the owner and group of the file described by fi to dst.
+// In this system, it does nothing.
+func (o *options) copyOwner(dst string, fi os.FileInfo) error { return nil }
+
+// sameOwner reports whether both files have the same owner and group.
+// In this system, it is always true.
+func sameOwner(fi1, fi2 os.FileInfo) bool { return true }
+
+// accessTime returns the time of the last access to the file.
+// In this system, it is used the modification time.
+func accessTime(fi os.FileInfo) time.Time {
+	return fi.ModTime()
+}
+
+// changeTime returns the time of the last change of the file status.
+// In this system, it is used the modification time.
+func changeTime(fi os.FileInfo) time.Time {
+	return fi.ModTime()
+}
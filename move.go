@@ -0,0 +1,236 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+var (
+	errVerify    = errors.New("the copy differs from the source")
+	errIrregular = errors.New("not a regular file, directory or symbolic link")
+)
+
+// Move moves the file or directory at 'source' to 'dest'.
+// It makes a backup of the file 'dest' if the global variable 'DoBackup' is
+// set to true, unless it is set by the option WithBackup.
+//
+// It is renamed when it is possible. Else, when they are at different
+// filesystems, it is copied preserving all metadata, unless it is set by the
+// option WithPreserve; the copy is synced to disk and verified against the
+// source before of removing the source.
+func Move(source, dest string, opt ...Option) error {
+	o := newOptions(opt)
+	if !o.preserveSet {
+		o.preserve = PreserveAll
+	}
+
+	info, err := os.Lstat(source)
+	if err != nil {
+		return err
+	}
+
+	// Don't backup files of backup.
+	if o.backup && !info.IsDir() && dest[len(dest)-1] != '~' {
+		if _, err = o.backupFile(dest); err != nil {
+			return err
+		}
+	}
+
+	if err = os.Rename(source, dest); err == nil {
+		o.log.Printf("File %q moved to %q", source, dest)
+		return nil
+	}
+	if !isCrossDevice(err) {
+		return err
+	}
+
+	switch mode := info.Mode(); {
+	case mode.IsDir():
+		err = o.moveDir(source, dest)
+	case mode&os.ModeSymlink != 0:
+		err = o.moveLink(source, dest)
+	case mode.IsRegular():
+		err = o.moveFile(source, dest)
+	default:
+		return &os.PathError{Op: "move", Path: source, Err: errIrregular}
+	}
+	if err != nil {
+		return err
+	}
+
+	o.log.Printf("File %q moved to %q", source, dest)
+	return nil
+}
+
+// moveFile moves the regular file 'source' to another filesystem.
+func (o *options) moveFile(source, dest string) error {
+	o2 := *o
+	o2.atomic, o2.sync = true, SyncDir
+
+	if err := o2.copyFile(source, dest); err != nil {
+		return err
+	}
+	if err := verifyCopy(source, dest); err != nil {
+		return err
+	}
+
+	return os.Remove(source)
+}
+
+// moveLink moves the symbolic link 'source' to another filesystem.
+func (o *options) moveLink(source, dest string) error {
+	link, err := os.Readlink(source)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(dest); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err = os.Symlink(link, dest); err != nil {
+		return err
+	}
+	return os.Remove(source)
+}
+
+// moveDir moves the directory 'source' to another filesystem.
+// The directory is copied into a temporary directory, at the directory of
+// 'dest', which is renamed to 'dest' when it has been synced and verified.
+//
+// It fails before of copying if there is some named pipe, socket or device,
+// since they can not be copied, so they would be lost at removing 'source'.
+func (o *options) moveDir(source, dest string) (err error) {
+	err = filepath.Walk(source, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if mode := fi.Mode(); !mode.IsDir() && !mode.IsRegular() && mode&os.ModeSymlink == 0 {
+			return &os.PathError{Op: "move", Path: path, Err: errIrregular}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dest), filepath.Base(dest)+"."+prefixTemp)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if err2 := os.RemoveAll(tmpDir); err2 != nil {
+				o.log.Printf("Temporary directory %q not removed: %s", tmpDir, err2)
+			}
+		}
+	}()
+
+	o2 := *o
+	o2.followLinks = false
+	o2.include, o2.exclude = nil, nil
+	o2.conflict = ConflictOverwrite
+
+	if err = o2.copyDir(source, tmpDir); err != nil {
+		return err
+	}
+
+	err = filepath.Walk(tmpDir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return syncDir(path)
+		}
+		if fi.Mode().IsRegular() {
+			return syncFile(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = filepath.Walk(source, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(tmpDir, rel)
+
+		switch mode := fi.Mode(); {
+		case mode.IsRegular():
+			return verifyCopy(path, target)
+		case mode&os.ModeSymlink != 0:
+			return verifyLink(path, target)
+		}
+
+		if fi, err = os.Lstat(target); err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			return &os.PathError{Op: "verify", Path: target, Err: errVerify}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpDir, dest); err != nil {
+		return err
+	}
+	if err = syncDir(filepath.Dir(dest)); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(source)
+}
+
+// verifyCopy checks that the file 'dest' has the same content than 'source'.
+func verifyCopy(source, dest string) error {
+	srcInfo, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	dstInfo, err := os.Stat(dest)
+	if err != nil {
+		return err
+	}
+
+	same, err := sameContent(source, srcInfo, dest, dstInfo)
+	if err != nil {
+		return err
+	}
+	if !same {
+		return &os.PathError{Op: "verify", Path: dest, Err: errVerify}
+	}
+	return nil
+}
+
+// verifyLink checks that the symbolic link 'dest' points to the same file than
+// 'source'.
+func verifyLink(source, dest string) error {
+	want, err := os.Readlink(source)
+	if err != nil {
+		return err
+	}
+	got, err := os.Readlink(dest)
+	if err != nil {
+		return err
+	}
+
+	if got != want {
+		return &os.PathError{Op: "verify", Path: dest, Err: errVerify}
+	}
+	return nil
+}
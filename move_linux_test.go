@@ -0,0 +1,47 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestMoveDirIrregular(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	dest := filepath.Join(dir, "dest")
+	fifo := filepath.Join(source, "sub", "fifo")
+
+	if err := os.MkdirAll(filepath.Dir(fifo), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "foo"), []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Used when the rename fails across filesystems.
+	o := newOptions(nil)
+	if err := o.moveDir(source, dest); !errors.Is(err, errIrregular) {
+		t.Fatalf("got error %v, want %v", err, errIrregular)
+	}
+	if _, err := os.Lstat(fifo); err != nil {
+		t.Errorf("source changed: %v", err)
+	}
+	if files, _ := filepath.Glob(dest + "*"); len(files) != 0 {
+		t.Errorf("files created: %q", files)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !plan9
+// +build !plan9
+
+package fileutil
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDevice reports whether the error of a rename is due to the files are
+// at different filesystems.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMove(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+
+	if err := os.MkdirAll(filepath.Join(source, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "sub", "foo"), []byte("foo"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("sub/foo", filepath.Join(source, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(dir, "dest")
+	if err := Move(source, dest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("source %q not removed", source)
+	}
+
+	// Used when the rename fails across filesystems.
+	o := newOptions(nil)
+	o.preserve = PreserveAll
+
+	source, dest = dest, filepath.Join(dir, "dest2")
+	if err := o.moveDir(source, dest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("source %q not removed", source)
+	}
+	if fi, err := os.Lstat(filepath.Join(dest, "link")); err != nil {
+		t.Error(err)
+	} else if fi.Mode()&os.ModeSymlink == 0 {
+		t.Error("symbolic link not moved as link")
+	}
+
+	source, dest = filepath.Join(dest, "sub", "foo"), filepath.Join(dir, "foo")
+	if err := o.moveFile(source, dest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("source %q not removed", source)
+	}
+	fi, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0640 {
+		t.Errorf("got mode %v, want %v", fi.Mode().Perm(), os.FileMode(0640))
+	}
+}
+
+func TestVerifyLink(t *testing.T) {
+	dir := t.TempDir()
+	link1 := filepath.Join(dir, "link1")
+	link2 := filepath.Join(dir, "link2")
+
+	if err := os.Symlink("foo", link1); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("bar", link2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verifyLink(link1, link1); err != nil {
+		t.Error(err)
+	}
+	if err := verifyLink(link1, link2); !errors.Is(err, errVerify) {
+		t.Errorf("got error %v, want %v", err, errVerify)
+	}
+}
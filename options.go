@@ -0,0 +1,312 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"context"
+	"fmt"
+	"hash"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+// An Option sets a setting used by the functions which write files.
+// The settings not given use the global variables as default values.
+type Option func(*options)
+
+// options represents the settings used at writing a file.
+type options struct {
+	backup       bool
+	backupSet    bool // 'backup' was set by an option
+	retention    Retention
+	retentionSet bool // 'retention' was set by an option
+
+	atomic    bool
+	atomicSet bool // 'atomic' was set by an option
+	perm      os.FileMode
+	permSet   bool // 'perm' was set by an option
+
+	method    CopyMethod
+	ctx       context.Context
+	progress  func(Progress)
+	rateLimit int64
+	resume    bool
+
+	sync      Durability
+	exclusive bool
+	mkdirPerm os.FileMode
+	uid, gid  int
+	ownerSet  bool             // 'uid' and 'gid' were set by an option
+	newHash   func() hash.Hash // to compute the checksum at copying
+	verify    bool
+
+	preserve    Preserve
+	preserveSet bool // 'preserve' was set by an option
+	log         *log.Logger
+
+	// Used at copying directories.
+	followLinks bool
+	include     []string
+	exclude     []string
+	conflict    Conflict
+	workers     int
+}
+
+// newOptions returns the settings given in opt.
+func newOptions(opt []Option) *options {
+	o := &options{
+		backup:  DoBackup,
+		perm:    0666,
+		log:     Log,
+		workers: 1,
+	}
+	for _, f := range opt {
+		f(o)
+	}
+	return o
+}
+
+// WithBackup sets whether a backup is made before of modify a file.
+// By default, it is used the global variable 'DoBackup'.
+func WithBackup(ok bool) Option {
+	return func(o *options) {
+		o.backup, o.backupSet = ok, true
+	}
+}
+
+// WithAtomic sets whether a file is written into a temporary file, at the same
+// directory, which is renamed to the file at the end. So the file is never
+// seen partially written. It is false by default, but at CopyContext.
+func WithAtomic(ok bool) Option {
+	return func(o *options) {
+		o.atomic, o.atomicSet = ok, true
+	}
+}
+
+// WithPerm sets the permissions used at creating a file, before of the umask.
+// By default, it is used 0666, or the permissions of the source file at
+// copying.
+func WithPerm(perm os.FileMode) Option {
+	return func(o *options) {
+		o.perm, o.permSet = perm&os.ModePerm, true
+	}
+}
+
+// WithExclusive sets whether a file is only created if it does not exist.
+// Else, it is returned an *ExistError.
+func WithExclusive(ok bool) Option {
+	return func(o *options) { o.exclusive = ok }
+}
+
+// WithMkdirAll sets that the parent directories of a file are created, if they
+// do not exist, with the permissions perm, before of the umask.
+func WithMkdirAll(perm os.FileMode) Option {
+	return func(o *options) { o.mkdirPerm = perm & os.ModePerm }
+}
+
+// WithOwner sets the numeric uid and gid of the owner of a file written.
+// A value of -1 means to not change that value.
+func WithOwner(uid, gid int) Option {
+	return func(o *options) {
+		o.uid, o.gid, o.ownerSet = uid, gid, true
+	}
+}
+
+// An ExistError is returned when a file to create exclusively already exists.
+type ExistError struct {
+	Name string
+}
+
+func (e *ExistError) Error() string { return fmt.Sprintf("file %q already exists", e.Name) }
+
+// Unwrap returns os.ErrExist, so 'errors.Is(err, os.ErrExist)' is true.
+func (e *ExistError) Unwrap() error { return os.ErrExist }
+
+// WithLogger sets the logger to use. By default, it is used the global logger
+// 'Log'.
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) { o.log = l }
+}
+
+// A Conflict value is the policy used when a file to copy already exists.
+type Conflict uint8
+
+// Policies used when a file to copy already exists.
+const (
+	ConflictOverwrite Conflict = iota // Overwrite the file.
+	ConflictSkip                      // Keep the file.
+	ConflictBackup                    // Make a backup of the file, and overwrite it.
+)
+
+// WithFollowLinks sets whether the symbolic links are followed at copying
+// directories, so it is copied the file or directory they point to.
+// By default, they are copied as links.
+func WithFollowLinks(ok bool) Option {
+	return func(o *options) { o.followLinks = ok }
+}
+
+// WithInclude sets the patterns of files to copy at copying directories; the
+// rest of files are skipped. The syntax is the one used by filepath.Match, and
+// a pattern is matched against the base name and the path relative to the
+// directory.
+func WithInclude(pattern ...string) Option {
+	return func(o *options) { o.include = append(o.include, pattern...) }
+}
+
+// WithExclude sets the patterns of files and directories to skip at copying
+// directories. The patterns are matched like at WithInclude.
+func WithExclude(pattern ...string) Option {
+	return func(o *options) { o.exclude = append(o.exclude, pattern...) }
+}
+
+// WithConflict sets the policy used at copying directories when a file already
+// exists at the destination. By default, it is overwritten.
+func WithConflict(c Conflict) Option {
+	return func(o *options) { o.conflict = c }
+}
+
+// WithWorkers sets the number of files copied in parallel at copying
+// directories. By default, it is 1.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			n = 1
+		}
+		o.workers = n
+	}
+}
+
+// * * *
+
+// writeFile opens the named file to write, truncating it or creating it with
+// the permissions o.perm, and calls fn with it.
+//
+// At atomic mode, fn writes into a temporary file which is renamed to the named
+// file, keeping the permissions and owner of the file, if it exists.
+func (o *options) writeFile(name string, fn func(*os.File) error) (err error) {
+	if o.mkdirPerm != 0 {
+		if err = os.MkdirAll(filepath.Dir(name), o.mkdirPerm); err != nil {
+			return err
+		}
+	}
+
+	if !o.atomic {
+		flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if o.exclusive {
+			flag = os.O_WRONLY | os.O_CREATE | os.O_EXCL
+		}
+
+		file, err := os.OpenFile(name, flag, o.perm)
+		if err != nil {
+			if o.exclusive && os.IsExist(err) {
+				return &ExistError{name}
+			}
+			return err
+		}
+
+		if err = o.setOwner(file); err == nil {
+			err = fn(file)
+		}
+		if err == nil && o.sync >= SyncFile {
+			err = file.Sync()
+		}
+		err2 := file.Close()
+		if err2 != nil && err == nil {
+			err = err2
+		}
+		if err != nil {
+			return err
+		}
+		return o.syncDirOf(name)
+	}
+
+	if o.exclusive {
+		if _, err = os.Lstat(name); err == nil {
+			return &ExistError{name}
+		}
+	}
+
+	// Like a write not atomic, it is replaced the file pointed by a link.
+	if path, err := filepath.EvalSymlinks(name); err == nil {
+		name = path
+	}
+
+	file, err := createTemp(name, o.perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil || o.exclusive {
+			if err2 := os.Remove(file.Name()); err2 != nil && !os.IsNotExist(err2) {
+				o.log.Printf("Temporary file %q not removed: %s", file.Name(), err2)
+			}
+		}
+	}()
+
+	if info, err := os.Stat(name); err == nil {
+		if err = file.Chmod(info.Mode() & modeMask); err != nil {
+			file.Close()
+			return err
+		}
+		if err = o.copyOwner(file.Name(), info); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if err = o.setOwner(file); err == nil {
+		err = fn(file)
+	}
+	if err == nil && o.sync >= SyncFile {
+		err = file.Sync()
+	}
+	err2 := file.Close()
+	if err2 != nil && err == nil {
+		err = err2
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.exclusive {
+		// A link fails if the file exists, unlike a rename.
+		if err = os.Link(file.Name(), name); err != nil {
+			if os.IsExist(err) {
+				return &ExistError{name}
+			}
+			return err
+		}
+	} else if err = os.Rename(file.Name(), name); err != nil {
+		return err
+	}
+	return o.syncDirOf(name)
+}
+
+// setOwner sets the owner of the file, if it was set by WithOwner.
+func (o *options) setOwner(file *os.File) error {
+	if !o.ownerSet {
+		return nil
+	}
+	return file.Chown(o.uid, o.gid)
+}
+
+// createTemp creates a new file, with a name made from the named file, at the
+// same directory. The file is created with the permissions perm, before of the
+// umask.
+func createTemp(name string, perm os.FileMode) (*os.File, error) {
+	for i := 0; ; i++ {
+		tmpName := fmt.Sprintf("%s.%s%d", name, prefixTemp, rand.Uint32())
+
+		file, err := os.OpenFile(tmpName, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if os.IsExist(err) && i < 10000 {
+			continue
+		}
+		return file, err
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Progress represents the progress of a copy.
+type Progress struct {
+	Done  int64   // Bytes copied.
+	Total int64   // Size of the file.
+	Rate  float64 // Bytes copied by second.
+}
+
+// WithProgress sets a function called with the progress of the copy of a file,
+// after of every block copied.
+func WithProgress(fn func(Progress)) Option {
+	return func(o *options) { o.progress = fn }
+}
+
+// WithRateLimit sets the maximum number of bytes copied by second.
+func WithRateLimit(bytesPerSec int64) Option {
+	return func(o *options) { o.rateLimit = bytesPerSec }
+}
+
+// CopyContext is like Copy, but the copy is stopped when the context is done,
+// returning the error of the context.
+//
+// The copy is atomic by default (see WithAtomic), so a copy stopped leaves the
+// destination file as it was. Without atomic mode, it is removed the
+// destination file only if it was created by this copy; at resuming (see
+// WithResume), it is kept to continue the copy later.
+//
+// It uses the options WithProgress and WithRateLimit, if any.
+// The content is copied in user space (see WithCopyMethod).
+func CopyContext(ctx context.Context, source, dest string, opt ...Option) (err error) {
+	o := newOptions(opt)
+	o.ctx = ctx
+	if !o.atomicSet && !o.resume {
+		o.atomic = true
+	}
+
+	_, err = o.copy(source, dest)
+	return err
+}
+
+// CopytoTempContext is like CopytoTemp, but the copy is stopped when the
+// context is done, removing the temporary file.
+//
+// It uses the options WithProgress and WithRateLimit, if any.
+func CopytoTempContext(ctx context.Context, source, prefix string, opt ...Option) (tmpFile string, err error) {
+	o := newOptions(opt)
+	o.ctx = ctx
+
+	dest, err := o.copytoTemp(source, "", prefix)
+	if err != nil {
+		return "", err
+	}
+	if err = dest.Close(); err != nil {
+		return "", err
+	}
+	return dest.Name(), nil
+}
+
+// copyContext copies src to dst by blocks, checking the context, reporting the
+// progress and limiting the rate, as they are set in o. The source file has
+// size total, and it has already been copied 'done' bytes.
+func (o *options) copyContext(dst io.Writer, src io.Reader, done, total int64) error {
+	ctx := o.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	buf := make([]byte, 256<<10)
+	start := time.Now()
+	copied := int64(0) // at this call
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return err
+			}
+			done += int64(n)
+			copied += int64(n)
+
+			elapsed := time.Since(start)
+			if o.progress != nil {
+				p := Progress{Done: done, Total: total}
+				if elapsed > 0 {
+					p.Rate = float64(copied) / elapsed.Seconds()
+				}
+				o.progress(p)
+			}
+
+			if o.rateLimit > 0 {
+				want := time.Duration(float64(copied) / float64(o.rateLimit) * float64(time.Second))
+				if wait := want - elapsed; wait > 0 {
+					timer := time.NewTimer(wait)
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return ctx.Err()
+					case <-timer.C:
+					}
+				}
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
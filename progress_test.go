@@ -0,0 +1,83 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyContext(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	dest := filepath.Join(dir, "dest")
+	const size = 1 << 20
+
+	if err := os.WriteFile(source, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var last Progress
+	err := CopyContext(context.Background(), source, dest,
+		WithProgress(func(p Progress) { last = p }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Done != size || last.Total != size {
+		t.Errorf("got progress %d/%d, want %d/%d", last.Done, last.Total, size, size)
+	}
+
+	// Cancel after of the first block.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err = CopyContext(ctx, source, dest, WithBackup(false),
+		WithProgress(func(Progress) { cancel() }))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if info, err := os.Stat(dest); err != nil || info.Size() != size {
+		t.Errorf("file %q changed: %v", dest, err)
+	}
+	if files, _ := filepath.Glob(dest + ".*"); len(files) != 0 {
+		t.Errorf("temporary files not removed: %q", files)
+	}
+
+	// Without atomic mode, it is only removed the file created by the copy.
+	for _, opt := range [][]Option{
+		{WithAtomic(false)},
+		{WithAtomic(false), WithExclusive(true)},
+	} {
+		err = CopyContext(ctx, source, dest, append(opt, WithBackup(false))...)
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, os.ErrExist) {
+			t.Fatalf("got error %v", err)
+		}
+		if _, err = os.Stat(dest); err != nil {
+			t.Errorf("file %q removed: %v", dest, err)
+		}
+	}
+
+	newDest := filepath.Join(dir, "new")
+	err = CopyContext(ctx, source, newDest, WithAtomic(false))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if _, err = os.Stat(newDest); !os.IsNotExist(err) {
+		t.Errorf("file %q not removed", newDest)
+	}
+
+	name, err := CopytoTempContext(ctx, source, "")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if name != "" {
+		t.Errorf("got temporary file %q", name)
+	}
+}
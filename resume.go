@@ -0,0 +1,97 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"os"
+)
+
+// WithResume sets whether an interrupted copy is continued. If the destination
+// file is smaller than the source file, and its content is the start of the
+// source file, checked by their SHA-256 checksums, it is only copied the rest.
+// Else, the file is copied from the start.
+//
+// It is not made a backup of the destination file, and at resuming it is not
+// used the atomic mode. A copy cancelled (see CopyContext) is not removed.
+func WithResume(ok bool) Option {
+	return func(o *options) { o.resume = ok }
+}
+
+// resumeCopy continues the copy of src, described by info, to the named file,
+// if it has the start of src. The content read from src is also written in h,
+// if it is not nil.
+//
+// It reports whether the copy has been resumed; else, src is at its start, and
+// it has to be copied from there.
+func (o *options) resumeCopy(src *os.File, info os.FileInfo, dest string, h hash.Hash) (ok bool, err error) {
+	dstInfo, err := os.Stat(dest)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	done := dstInfo.Size()
+	if !dstInfo.Mode().IsRegular() || done == 0 || done > info.Size() {
+		return false, nil
+	}
+
+	// Checksum of the start of the source file.
+	hStart := sha256.New()
+	var w io.Writer = hStart
+	if h != nil {
+		w = io.MultiWriter(hStart, h)
+	}
+	if _, err = io.CopyN(w, src, done); err != nil {
+		return false, err
+	}
+
+	sum, err := hashFile(dest)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(hStart.Sum(nil), sum) {
+		if h != nil {
+			h.Reset()
+		}
+		_, err = src.Seek(0, io.SeekStart)
+		return false, err
+	}
+
+	dst, err := os.OpenFile(dest, os.O_WRONLY, 0)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err2 := dst.Close(); err2 != nil && err == nil {
+			err = err2
+		}
+	}()
+	if _, err = dst.Seek(done, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	o.log.Printf("Copy of %q resumed at byte %d", src.Name(), done)
+
+	var r io.Reader = src
+	if h != nil {
+		r = io.TeeReader(src, h)
+	}
+	if err = o.copyContext(dst, r, done, info.Size()); err != nil {
+		return true, err
+	}
+	if o.sync >= SyncFile {
+		if err = dst.Sync(); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyResume(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	dest := filepath.Join(dir, "dest")
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<16)
+	half := int64(len(data) / 2)
+
+	if err := os.WriteFile(source, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		partial []byte
+		resumed bool
+	}{
+		{data[:half], true},
+		{bytes.Repeat([]byte{'x'}, int(half)), false},
+	} {
+		if err := os.WriteFile(dest, v.partial, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		first := int64(-1)
+		err := CopyContext(context.Background(), source, dest, WithResume(true),
+			WithProgress(func(p Progress) {
+				if first == -1 {
+					first = p.Done
+				}
+			}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resumed := first > half; resumed != v.resumed {
+			t.Errorf("got resumed %v, want %v", resumed, v.resumed)
+		}
+		got, err := os.ReadFile(dest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Error("the copy differs")
+		}
+	}
+}
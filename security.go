@@ -0,0 +1,67 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// A SecurityReport has the files found by ScanSecurity which could be a risk.
+type SecurityReport struct {
+	WorldWritable []string // files, but directories, writable by others
+	Setuid        []string // regular files with the set-user-ID bit
+	Setgid        []string // regular files with the set-group-ID bit
+	NoSticky      []string // directories writable by others without the sticky bit
+}
+
+// ScanSecurity walks the directory tree at 'dir', without following symbolic
+// links, looking for the files which could be a risk for the security.
+//
+// The directories which can not be read by lack of privileges are skipped.
+func ScanSecurity(dir string) (*SecurityReport, error) {
+	report := new(SecurityReport)
+
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if fi != nil && fi.IsDir() && os.IsPermission(err) {
+				Log.Printf("Directory %q skipped: %s", path, err)
+				return nil
+			}
+			return err
+		}
+
+		mode := fi.Mode()
+		switch {
+		case mode&os.ModeSymlink != 0:
+			return nil
+
+		case mode.IsDir():
+			if mode&modeWOthers != 0 && mode&os.ModeSticky == 0 {
+				report.NoSticky = append(report.NoSticky, path)
+			}
+			return nil
+
+		case mode&modeWOthers != 0:
+			report.WorldWritable = append(report.WorldWritable, path)
+		}
+
+		if mode.IsRegular() {
+			if mode&os.ModeSetuid != 0 {
+				report.Setuid = append(report.Setuid, path)
+			}
+			if mode&os.ModeSetgid != 0 {
+				report.Setgid = append(report.Setgid, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScanSecurity(t *testing.T) {
+	dir := t.TempDir()
+
+	shared := filepath.Join(dir, "shared")
+	tmp := filepath.Join(dir, "tmp")
+	public := filepath.Join(dir, "public")
+	bin := filepath.Join(dir, "bin")
+	safe := filepath.Join(dir, "safe")
+
+	for _, name := range []string{shared, tmp} {
+		if err := os.Mkdir(name, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(name, 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{public, bin, safe} {
+		if err := os.WriteFile(name, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(public, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetSticky(tmp, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetSetuid(bin, true); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := NewInfo(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsSetuid() || info.IsSetgid() || info.IsSticky() {
+		t.Errorf("got setuid %v, setgid %v, sticky %v; want true, false, false",
+			info.IsSetuid(), info.IsSetgid(), info.IsSticky())
+	}
+
+	report, err := ScanSecurity(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SecurityReport{
+		WorldWritable: []string{public},
+		Setuid:        []string{bin},
+		NoSticky:      []string{shared},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("got %+v, want %+v", report, want)
+	}
+
+	if err = SetSetuid(bin, false); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = NewInfo(bin); err != nil {
+		t.Fatal(err)
+	}
+	if info.IsSetuid() || info.Octal() != "0755" {
+		t.Errorf("got mode %s, want 0755", info.Octal())
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSnapshot is the number of snapshots kept by BackupDir, by default.
+// It is the same number of backups used in the rotation of files.
+const maxSnapshot = 9
+
+// layoutSnapshot is the layout of the time added to the name of a snapshot.
+// It is used UTC so the order of the names is the chronological order.
+const layoutSnapshot = "20060102T150405.000000000"
+
+// BackupDir creates a snapshot of the named directory, returning its name.
+//
+// The schema used for the new name is: {dir}+{time}~
+//
+//	dir: The original directory name, as absolute path.
+//	+ : Character used to separate the directory name from rest.
+//	time: The current time in UTC, with the layout 'layoutSnapshot'.
+//	~ : To indicate that it is a backup, just like it is used in Unix systems.
+//
+// If dir is a symbolic link, it is copied the directory pointed by it. It is
+// returned an error if the snapshot would be created into that directory.
+//
+// The files are copied keeping their metadata, like at Backup. The regular
+// files not changed since the previous snapshot, with the same size,
+// modification time, mode and owner, are hard links to the files of that
+// snapshot, so every snapshot takes only the space of the files changed.
+//
+// The symbolic links are copied as links, and the named pipes, sockets and
+// devices are skipped. At the end, they are only kept the last 9 snapshots,
+// unless it is set other policy by the option WithRetention (see
+// PruneSnapshots).
+func BackupDir(dir string, opt ...Option) (snapshot string, err error) {
+	if dir, err = filepath.Abs(dir); err != nil {
+		return "", err
+	}
+	// If it is a symbolic link, it is walked the directory pointed by it.
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", &os.PathError{Op: "backupdir", Path: dir, Err: errNotDir}
+	}
+
+	snapshots, err := listSnapshots(dir)
+	if err != nil {
+		return "", err
+	}
+	prev := ""
+	if len(snapshots) != 0 {
+		prev = snapshots[len(snapshots)-1]
+	}
+
+	snapshot = dir + "+" + time.Now().UTC().Format(layoutSnapshot) + "~"
+	partial := snapshot + ".partial"
+
+	// Else, every snapshot would be copied into the next one, like at "/".
+	snapPath, err := realPath(snapshot)
+	if err != nil {
+		return "", err
+	}
+	if isUnder(snapPath, root) {
+		return "", &os.PathError{Op: "backupdir", Path: snapshot, Err: errIntoItself}
+	}
+
+	o := newOptions(opt)
+	if !o.preserveSet {
+		o.preserve = PreserveAll
+	}
+
+	o.log.Printf("Creating snapshot of %q", dir)
+	if err = o.copySnapshot(root, partial, prev); err != nil {
+		if err2 := os.RemoveAll(partial); err2 != nil {
+			o.log.Printf("Partial snapshot %q not removed: %s", partial, err2)
+		}
+		return "", err
+	}
+	if err = os.Rename(partial, snapshot); err != nil {
+		return "", err
+	}
+
+	o.log.Printf("Directory %q copied at %q", dir, snapshot)
+
+	r := Retention{Last: maxSnapshot}
+	if o.retentionSet {
+		r = o.retention
+	}
+	if _, err = o.pruneSnapshots(dir, r, false); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// PruneSnapshots deletes the snapshots of the named directory which are not
+// kept by the retention policy r, like at PruneBackups. The size of a snapshot
+// is the one of its regular files, although the files not changed are shared
+// with other snapshots.
+//
+// If dryRun is true, it only reports what would be deleted.
+func PruneSnapshots(dir string, r Retention, dryRun bool) (*PruneReport, error) {
+	return newOptions(nil).pruneSnapshots(dir, r, dryRun)
+}
+
+// pruneSnapshots deletes the snapshots of the named directory like
+// PruneSnapshots, using the logger in o.
+func (o *options) pruneSnapshots(dir string, r Retention, dryRun bool) (*PruneReport, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := listSnapshots(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// From the newest.
+	entries := make([]pruneEntry, len(snapshots))
+	for i, snap := range snapshots {
+		t, _ := time.Parse(layoutSnapshot, snapshotStamp(dir, snap))
+
+		size, err := dirSize(snap)
+		if err != nil {
+			return nil, err
+		}
+		entries[len(snapshots)-1-i] = pruneEntry{snap, t, size}
+	}
+
+	return o.prune(entries, r, dryRun, os.RemoveAll)
+}
+
+var errNotDir = errors.New("not a directory")
+
+// listSnapshots returns the snapshots of the named directory, sorted from the
+// oldest to the newest.
+func listSnapshots(dir string) ([]string, error) {
+	files, err := filepath.Glob(dir + "+*~")
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]string, 0, len(files))
+	for _, f := range files {
+		if _, err = time.Parse(layoutSnapshot, snapshotStamp(dir, f)); err != nil {
+			continue
+		}
+		if fi, err := os.Lstat(f); err != nil || !fi.IsDir() {
+			continue
+		}
+		snapshots = append(snapshots, f)
+	}
+
+	sort.Strings(snapshots)
+	return snapshots, nil
+}
+
+// snapshotStamp returns the time, as text, in the name of a snapshot of dir.
+func snapshotStamp(dir, snapshot string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(snapshot, dir+"+"), "~")
+}
+
+// dirSize returns the size of the regular files into the directory tree.
+func dirSize(dir string) (size int64, err error) {
+	err = filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode().IsRegular() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// copySnapshot copies the tree at dir to dst, doing hard links to the files
+// of the snapshot prev which have not changed. prev can be empty.
+func (o *options) copySnapshot(dir, dst, prev string) error {
+	var dirs []string // directories whose metadata is set at the end
+
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch mode := fi.Mode(); {
+		case mode.IsDir():
+			if err = os.Mkdir(target, 0700); err != nil {
+				return err
+			}
+			dirs = append(dirs, rel)
+
+		case mode&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err = os.Symlink(link, target); err != nil {
+				return err
+			}
+			if err = o.copyOwner(target, fi); err != nil {
+				return err
+			}
+
+		case mode.IsRegular():
+			if prev != "" {
+				prevFile := filepath.Join(prev, rel)
+
+				if prevInfo, err := os.Lstat(prevFile); err == nil && sameStatus(fi, prevInfo) {
+					if err = os.Link(prevFile, target); err == nil {
+						return nil
+					}
+					o.log.Printf("Hard link to %q not created: %s", prevFile, err)
+				}
+			}
+			return o.copyFile(path, target)
+
+		default:
+			o.log.Printf("File %q skipped: not a regular file", path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The times of the directories change when their files are created.
+	for i := len(dirs) - 1; i >= 0; i-- {
+		src := filepath.Join(dir, dirs[i])
+
+		fi, err := os.Lstat(src)
+		if err != nil {
+			return err
+		}
+		if err = o.copyMeta(src, filepath.Join(dst, dirs[i]), fi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameStatus reports whether both files have the same size, modification time,
+// mode and owner.
+func sameStatus(fi1, fi2 os.FileInfo) bool {
+	return fi1.Size() == fi2.Size() &&
+		fi1.ModTime().Equal(fi2.ModTime()) &&
+		fi1.Mode() == fi2.Mode() &&
+		sameOwner(fi1, fi2)
+}
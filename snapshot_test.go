@@ -0,0 +1,230 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "conf")
+
+	if err := os.MkdirAll(filepath.Join(dir, "sub", "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "b"), []byte("b\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("sub/b", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	snap1, err := BackupDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Lstat(filepath.Join(snap1, "link")); err != nil {
+		t.Error(err)
+	} else if fi.Mode()&os.ModeSymlink == 0 {
+		t.Error("symbolic link not copied as link")
+	}
+	if _, err = os.Stat(filepath.Join(snap1, "sub", "empty")); err != nil {
+		t.Error(err)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, "a"), []byte("aa\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	snap2, err := BackupDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		name   string
+		linked bool
+	}{
+		{"a", false},
+		{filepath.Join("sub", "b"), true},
+	} {
+		fi1, err := os.Stat(filepath.Join(snap1, v.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fi2, err := os.Stat(filepath.Join(snap2, v.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if os.SameFile(fi1, fi2) != v.linked {
+			t.Errorf("%s: got hard link %v, want %v", v.name, !v.linked, v.linked)
+		}
+	}
+
+	// Retention
+	for i := 0; i < maxSnapshot; i++ {
+		if _, err = BackupDir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshots, err := listSnapshots(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != maxSnapshot {
+		t.Errorf("got %d snapshots, want %d", len(snapshots), maxSnapshot)
+	}
+	if _, err = os.Stat(snap1); !os.IsNotExist(err) {
+		t.Errorf("snapshot %q not removed", snap1)
+	}
+
+	report, err := PruneSnapshots(dir, Retention{Last: 3}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Kept) != 3 || len(report.Deleted) != maxSnapshot-3 {
+		t.Errorf("got %d kept and %d deleted, want 3 and %d",
+			len(report.Kept), len(report.Deleted), maxSnapshot-3)
+	}
+	if report.Kept[0] != snapshots[len(snapshots)-1] {
+		t.Errorf("got newest %q, want %q", report.Kept[0], snapshots[len(snapshots)-1])
+	}
+	if report.Freed == 0 {
+		t.Error("got no size freed")
+	}
+	if snapshots, err = listSnapshots(dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != maxSnapshot {
+		t.Errorf("dry run: got %d snapshots, want %d", len(snapshots), maxSnapshot)
+	}
+
+	var buf bytes.Buffer
+	snap, err := BackupDir(dir, WithRetention(Retention{Last: 2}), WithLogger(log.New(&buf, "", 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Removing backup") {
+		t.Errorf("pruning not logged with the logger given: %q", buf.String())
+	}
+	if snapshots, err = listSnapshots(dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[1] != snap {
+		t.Errorf("got snapshots %q, want 2 ending in %q", snapshots, snap)
+	}
+
+	// The new snapshot is kept, although it is greater than MaxBytes.
+	snap, err = BackupDir(dir, WithRetention(Retention{MaxBytes: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshots, err = listSnapshots(dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || snapshots[0] != snap {
+		t.Errorf("got snapshots %q, want %q", snapshots, snap)
+	}
+}
+
+func TestBackupDirCurrent(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "conf")
+
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for i := 0; i < 2; i++ {
+		snap, err := BackupDir(".")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Dir(snap) != filepath.Dir(dir) {
+			t.Errorf("got snapshot %q, want it beside %q", snap, dir)
+		}
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("got %d files into the directory, want 1", len(files))
+	}
+
+	// The snapshot would be into the directory.
+	if _, err = BackupDir("/"); !errors.Is(err, errIntoItself) {
+		t.Errorf("got error %v, want %v", err, errIntoItself)
+	}
+	if err = os.Symlink("..", "up"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = BackupDir("up"); !errors.Is(err, errIntoItself) {
+		t.Errorf("got error %v, want %v", err, errIntoItself)
+	}
+}
+
+func TestBackupDirLink(t *testing.T) {
+	tmp := t.TempDir()
+	target := filepath.Join(tmp, "real")
+	dir := filepath.Join(tmp, "conf")
+
+	if err := os.Mkdir(target, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(target, "a"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("real", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		snap, err := BackupDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi, err := os.Lstat(snap); err != nil {
+			t.Fatal(err)
+		} else if !fi.IsDir() {
+			t.Fatalf("snapshot %q is not a directory", snap)
+		}
+		if b, err := os.ReadFile(filepath.Join(snap, "a")); err != nil {
+			t.Error(err)
+		} else if string(b) != "a\n" {
+			t.Errorf("got %q, want %q", b, "a\n")
+		}
+	}
+
+	snapshots, err := listSnapshots(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("got %d snapshots, want 2", len(snapshots))
+	}
+}
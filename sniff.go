@@ -0,0 +1,344 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffLen is the number of bytes read from the start of a file to detect its
+// content.
+const sniffLen = 8192
+
+// A Magic represents a type of file detected by its first bytes.
+type Magic uint8
+
+// Types of files detected by their first bytes.
+const (
+	MagicNone    Magic = iota // No type detected.
+	MagicELF                  // Executable and Linkable Format.
+	MagicGzip                 // Compressed by gzip.
+	MagicZip                  // Archive zip, also used by jar, docx, odt...
+	MagicPNG                  // Image PNG.
+	MagicPDF                  // Document PDF.
+	MagicShebang              // Script with an interpreter, started by "#!".
+)
+
+var magicNames = [...]string{
+	MagicNone:    "none",
+	MagicELF:     "elf",
+	MagicGzip:    "gzip",
+	MagicZip:     "zip",
+	MagicPNG:     "png",
+	MagicPDF:     "pdf",
+	MagicShebang: "shebang",
+}
+
+func (m Magic) String() string {
+	if int(m) < len(magicNames) {
+		return magicNames[m]
+	}
+	return "unknown"
+}
+
+// magicNumbers are the first bytes of every type of file.
+var magicNumbers = []struct {
+	magic  Magic
+	prefix string
+}{
+	{MagicELF, "\x7fELF"},
+	{MagicGzip, "\x1f\x8b"},
+	{MagicZip, "PK\x03\x04"},
+	{MagicZip, "PK\x05\x06"}, // empty archive
+	{MagicPNG, "\x89PNG\r\n\x1a\n"},
+	{MagicPDF, "%PDF-"},
+	{MagicShebang, "#!"},
+}
+
+// A LineEnding represents the characters used to end the lines of a text.
+type LineEnding uint8
+
+// Line endings.
+const (
+	LineNone  LineEnding = iota // There is no line ending.
+	LineLF                      // Unix, "\n".
+	LineCRLF                    // Windows, "\r\n".
+	LineCR                      // Classic Mac OS, "\r".
+	LineMixed                   // More than one type.
+)
+
+var lineEndingNames = [...]string{
+	LineNone:  "none",
+	LineLF:    "LF",
+	LineCRLF:  "CRLF",
+	LineCR:    "CR",
+	LineMixed: "mixed",
+}
+
+func (l LineEnding) String() string {
+	if int(l) < len(lineEndingNames) {
+		return lineEndingNames[l]
+	}
+	return "unknown"
+}
+
+// byteOrderMarks are the BOMs of the Unicode encodings. The ones of UTF-32 are
+// before of UTF-16 since "utf-32le" starts like "utf-16le".
+var byteOrderMarks = []struct {
+	charset string
+	bom     string
+}{
+	{"utf-8", "\xef\xbb\xbf"},
+	{"utf-32le", "\xff\xfe\x00\x00"},
+	{"utf-32be", "\x00\x00\xfe\xff"},
+	{"utf-16le", "\xff\xfe"},
+	{"utf-16be", "\xfe\xff"},
+}
+
+// Content represents the content of a file, detected from its first bytes.
+type Content struct {
+	Binary bool
+	Magic  Magic
+
+	// Used in text files.
+	Charset    string // "us-ascii", "utf-8", "utf-16le", "iso-8859-1"...
+	BOM        bool   // it starts with a byte order mark
+	LineEnding LineEnding
+
+	// Used in scripts: the interpreter got from the line "#!". If the
+	// interpreter is run by "/usr/bin/env", it is the program run by it.
+	Interpreter string
+}
+
+// MIME returns the MIME type of the content.
+func (c *Content) MIME() string {
+	switch c.Magic {
+	case MagicELF:
+		return "application/x-executable"
+	case MagicGzip:
+		return "application/gzip"
+	case MagicZip:
+		return "application/zip"
+	case MagicPNG:
+		return "image/png"
+	case MagicPDF:
+		return "application/pdf"
+	}
+
+	if c.Binary {
+		return "application/octet-stream"
+	}
+	return "text/plain; charset=" + c.Charset
+}
+
+// Sniff detects the content of the file, like at function Sniff.
+func (i Info) Sniff() (*Content, error) {
+	return Sniff(i.name)
+}
+
+// * * *
+
+// Sniff detects the content of the named file reading only its first bytes:
+// whether it is text or binary, the charset, the byte order mark, the line
+// ending, and the type of some common files by their magic numbers.
+func Sniff(name string) (*Content, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, sniffLen+1)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	// The extra byte is read to know whether the file is longer.
+	if n > sniffLen {
+		return sniffContent(buf[:sniffLen], true), nil
+	}
+	return sniffContent(buf[:n], false), nil
+}
+
+// IsText reports whether the named file has text, like at Sniff.
+func IsText(name string) (bool, error) {
+	c, err := Sniff(name)
+	if err != nil {
+		return false, err
+	}
+	return !c.Binary, nil
+}
+
+// sniffContent detects the content of buf. truncated reports whether buf is
+// only the start of the content.
+func sniffContent(buf []byte, truncated bool) *Content {
+	c := new(Content)
+
+	for _, m := range magicNumbers {
+		if bytes.HasPrefix(buf, []byte(m.prefix)) {
+			c.Magic = m.magic
+			break
+		}
+	}
+
+	var units []rune // code units of the text
+
+	for _, b := range byteOrderMarks {
+		if bytes.HasPrefix(buf, []byte(b.bom)) {
+			c.Charset, c.BOM = b.charset, true
+			buf = buf[len(b.bom):]
+			break
+		}
+	}
+
+	switch c.Charset {
+	case "utf-16le", "utf-16be", "utf-32le", "utf-32be":
+		width := 2
+		if strings.HasPrefix(c.Charset, "utf-32") {
+			width = 4
+		}
+		bigEndian := strings.HasSuffix(c.Charset, "be")
+
+		for i := 0; i+width <= len(buf); i += width {
+			var u rune
+			for j := 0; j < width; j++ {
+				k := j
+				if !bigEndian {
+					k = width - 1 - j
+				}
+				u = u<<8 | rune(buf[i+k])
+			}
+			units = append(units, u)
+		}
+
+	default:
+		if bytes.IndexByte(buf, 0) != -1 || isControl(buf) {
+			c.Binary = true
+			return c
+		}
+
+		text := buf
+		if truncated {
+			// The last character could be cut.
+			for i := 1; i < utf8.UTFMax && i <= len(text); i++ {
+				if utf8.RuneStart(text[len(text)-i]) {
+					if !utf8.FullRune(text[len(text)-i:]) {
+						text = text[:len(text)-i]
+					}
+					break
+				}
+			}
+		}
+
+		switch {
+		case c.Charset == "utf-8":
+		case isASCII(text):
+			c.Charset = "us-ascii"
+		case utf8.Valid(text):
+			c.Charset = "utf-8"
+		default:
+			c.Charset = "iso-8859-1"
+		}
+
+		for _, b := range text {
+			units = append(units, rune(b))
+		}
+	}
+
+	c.LineEnding = lineEnding(units)
+
+	if c.Magic == MagicShebang {
+		c.Interpreter = interpreter(units)
+	}
+	return c
+}
+
+// isASCII reports whether buf has only ASCII characters.
+func isASCII(buf []byte) bool {
+	for _, b := range buf {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// isControl reports whether buf has a lot of control characters, which are not
+// used in text files, so it is binary.
+func isControl(buf []byte) bool {
+	n := 0
+	for _, b := range buf {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != '\b' && b != 0x1b {
+			n++
+		}
+	}
+	return n*10 > len(buf)
+}
+
+// lineEnding returns the line ending used in the text.
+func lineEnding(units []rune) LineEnding {
+	var lf, crlf, cr int
+
+	for i := 0; i < len(units); i++ {
+		switch units[i] {
+		case '\n':
+			lf++
+		case '\r':
+			if i+1 < len(units) && units[i+1] == '\n' {
+				crlf++
+				i++
+			} else {
+				cr++
+			}
+		}
+	}
+
+	switch {
+	case lf == 0 && crlf == 0 && cr == 0:
+		return LineNone
+	case crlf == 0 && cr == 0:
+		return LineLF
+	case lf == 0 && cr == 0:
+		return LineCRLF
+	case lf == 0 && crlf == 0:
+		return LineCR
+	}
+	return LineMixed
+}
+
+// interpreter returns the interpreter of the line "#!".
+func interpreter(units []rune) string {
+	var line []rune
+	for _, u := range units[2:] {
+		if u == '\n' || u == '\r' {
+			break
+		}
+		line = append(line, u)
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	if filepath.Base(fields[0]) != "env" {
+		return fields[0]
+	}
+
+	// The options of env are skipped, like "-S".
+	for _, f := range fields[1:] {
+		if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+			return f
+		}
+	}
+	return ""
+}
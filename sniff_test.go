@@ -0,0 +1,74 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	dir := t.TempDir()
+
+	for i, v := range []struct {
+		data string
+		want Content
+		mime string
+	}{
+		{"foo\nbar\n", Content{Charset: "us-ascii", LineEnding: LineLF}, "text/plain; charset=us-ascii"},
+		{"foo\r\nbar", Content{Charset: "us-ascii", LineEnding: LineCRLF}, ""},
+		{"foo\rbar\n", Content{Charset: "us-ascii", LineEnding: LineMixed}, ""},
+		{"caf\xc3\xa9", Content{Charset: "utf-8"}, ""},
+		{"\xef\xbb\xbfcaf\xc3\xa9\r", Content{Charset: "utf-8", BOM: true, LineEnding: LineCR}, ""},
+		{"\xff\xfea\x00\r\x00\n\x00", Content{Charset: "utf-16le", BOM: true, LineEnding: LineCRLF}, ""},
+		{"caf\xe9\n", Content{Charset: "iso-8859-1", LineEnding: LineLF}, ""},
+		{"\x01\x02\x03\x04\x05", Content{Binary: true}, "application/octet-stream"},
+		{"\x7fELF\x02\x01\x01\x00", Content{Binary: true, Magic: MagicELF}, "application/x-executable"},
+		{"\x1f\x8b\x08\x00\x00", Content{Binary: true, Magic: MagicGzip}, "application/gzip"},
+		{"PK\x03\x04\x14\x00", Content{Binary: true, Magic: MagicZip}, "application/zip"},
+		{"\x89PNG\r\n\x1a\n\x00", Content{Binary: true, Magic: MagicPNG}, "image/png"},
+		{"%PDF-1.4\n%\xe2\xe3\xcf\xd3\n", Content{Charset: "iso-8859-1", Magic: MagicPDF, LineEnding: LineLF}, "application/pdf"},
+		{"#!/bin/sh\necho\n", Content{Charset: "us-ascii", Magic: MagicShebang, LineEnding: LineLF, Interpreter: "/bin/sh"}, ""},
+		{"#!/usr/bin/env -S python3 -u\n", Content{Charset: "us-ascii", Magic: MagicShebang, LineEnding: LineLF, Interpreter: "python3"}, ""},
+	} {
+		filename := filepath.Join(dir, "file")
+		if err := os.WriteFile(filename, []byte(v.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := Sniff(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != v.want {
+			t.Errorf("#%d: got %+v, want %+v", i, *got, v.want)
+		}
+		if v.mime != "" && got.MIME() != v.mime {
+			t.Errorf("#%d: got MIME %q, want %q", i, got.MIME(), v.mime)
+		}
+	}
+
+	// Only the start is read, where the last character is cut.
+	filename := filepath.Join(dir, "long")
+	data := strings.Repeat("a", sniffLen-1) + "\xc3\xa9" + "\x00"
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := NewInfo(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := info.Sniff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Binary || got.Charset != "us-ascii" {
+		t.Errorf("got %+v, want text us-ascii", *got)
+	}
+}
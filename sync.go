@@ -0,0 +1,66 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// A Durability value sets what is committed to disk after of writing a file,
+// so it is not lost by a crash or a power loss.
+type Durability uint8
+
+// Levels of durability.
+const (
+	SyncNone Durability = iota // Nothing; the system writes it later.
+	SyncFile                   // The content of the file (fsync).
+	SyncDir                    // The content of the file and its directory entry.
+)
+
+// WithSync sets what is committed to disk after of writing a file.
+// By default, it is SyncNone.
+//
+// It is used by Create, Overwrite and the copies of files, and by an Editer at
+// every change. SyncDir is required so a file created or renamed (see
+// WithAtomic) is kept after a crash.
+func WithSync(d Durability) Option {
+	return func(o *options) { o.sync = d }
+}
+
+// syncDirOf commits the directory entry of the named file, if it is used SyncDir.
+func (o *options) syncDirOf(name string) error {
+	if o.sync < SyncDir {
+		return nil
+	}
+	return syncDir(filepath.Dir(name))
+}
+
+// syncFile commits the content of the named file to disk.
+func syncFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	err = f.Sync()
+	err2 := f.Close()
+	if err2 != nil && err == nil {
+		err = err2
+	}
+	return err
+}
+
+// syncDir commits the entries of the named directory to disk.
+// Windows does not support it.
+func syncDir(name string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	return syncFile(name)
+}
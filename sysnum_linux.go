@@ -0,0 +1,46 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import "runtime"
+
+// Numbers of the system calls not defined at package syscall, by architecture.
+// A zero value means that it is not supported.
+var sysCopyFileRange = map[string]uintptr{
+	"386":      377,
+	"amd64":    326,
+	"arm":      391,
+	"arm64":    285,
+	"loong64":  285,
+	"mips":     4360,
+	"mipsle":   4360,
+	"mips64":   5320,
+	"mips64le": 5320,
+	"ppc64":    379,
+	"ppc64le":  379,
+	"riscv64":  285,
+	"s390x":    375,
+}[runtime.GOARCH]
+
+var sysStatx = map[string]uintptr{
+	"386":      383,
+	"amd64":    332,
+	"arm":      397,
+	"arm64":    291,
+	"loong64":  291,
+	"mips":     4366,
+	"mipsle":   4366,
+	"mips64":   5326,
+	"mips64le": 5326,
+	"ppc64":    383,
+	"ppc64le":  383,
+	"riscv64":  291,
+	"s390x":    379,
+}[runtime.GOARCH]
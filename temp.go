@@ -0,0 +1,176 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// TempSpace represents a set of temporary files and directories, which are
+// removed at closing it. It is safe for concurrent use.
+//
+// The files are created with permissions 0600, and the directories with 0700,
+// so only the owner can access to them.
+type TempSpace struct {
+	dir string // directory where the files are created
+
+	mu    sync.Mutex
+	files []*os.File
+	names []string // files and directories to remove
+}
+
+// NewTempSpace returns a TempSpace whose files and directories are created at
+// dir. If dir is the empty string, it is used the default directory for
+// temporary files (see os.TempDir).
+// You must use 'Close()' to remove them.
+func NewTempSpace(dir string) *TempSpace {
+	return &TempSpace{dir: dir}
+}
+
+// File creates a new temporary file, opened for reading and writing, whose
+// name begins with prefix. If prefix is the empty string, uses the default
+// value prefixTemp.
+func (ts *TempSpace) File(prefix string) (*os.File, error) {
+	if prefix == "" {
+		prefix = prefixTemp
+	}
+
+	f, err := os.CreateTemp(ts.dir, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.add(f, f.Name())
+	return f, nil
+}
+
+// Dir creates a new temporary directory whose name begins with prefix, and
+// returns its name. If prefix is the empty string, uses the default value
+// prefixTemp.
+func (ts *TempSpace) Dir(prefix string) (string, error) {
+	if prefix == "" {
+		prefix = prefixTemp
+	}
+
+	name, err := os.MkdirTemp(ts.dir, prefix)
+	if err != nil {
+		return "", err
+	}
+
+	ts.add(nil, name)
+	return name, nil
+}
+
+// CopytoTemp is like the function CopytoTemp, but the temporary file is created
+// into the TempSpace, and it is returned opened for reading and writing, at
+// its start.
+func (ts *TempSpace) CopytoTemp(source, prefix string, opt ...Option) (*os.File, error) {
+	f, err := newOptions(opt).copytoTemp(source, ts.dir, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.add(f, f.Name())
+	return f, nil
+}
+
+// Close closes the files and removes all files and directories created.
+// It returns the first error, if any.
+func (ts *TempSpace) Close() (err error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for _, f := range ts.files {
+		f.Close() // it could be closed by the caller
+	}
+	for i := len(ts.names) - 1; i >= 0; i-- {
+		if err2 := os.RemoveAll(ts.names[i]); err2 != nil && err == nil {
+			err = err2
+		}
+	}
+
+	ts.files, ts.names = nil, nil
+	return err
+}
+
+func (ts *TempSpace) add(f *os.File, name string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if f != nil {
+		ts.files = append(ts.files, f)
+	}
+	ts.names = append(ts.names, name)
+}
+
+// * * *
+
+// CopytoTempFile is like CopytoTemp, but the temporary file is created into
+// the directory dir, and it is returned opened for reading and writing, at its
+// start. If dir is the empty string, it is used the default directory for
+// temporary files (see os.TempDir).
+// The caller has to close and remove the file.
+func CopytoTempFile(source, dir, prefix string, opt ...Option) (*os.File, error) {
+	return newOptions(opt).copytoTemp(source, dir, prefix)
+}
+
+// copytoTemp copies the source file into a new temporary file, at the
+// directory dir, whose name begins with prefix. The file is returned at its
+// start. On error, it is removed.
+func (o *options) copytoTemp(source, dir, prefix string) (_ *os.File, err error) {
+	if prefix == "" {
+		prefix = prefixTemp
+	}
+
+	src, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err2 := src.Close(); err2 != nil && err == nil {
+			err = err2
+		}
+	}()
+
+	info, err := src.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(dir, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			if err2 := os.Remove(f.Name()); err2 != nil {
+				o.log.Printf("File %q not removed: %s", f.Name(), err2)
+			}
+		}
+	}()
+
+	if err = o.copyContent(f, src, info, nil); err != nil {
+		return nil, err
+	}
+	if o.sync >= SyncFile {
+		if err = f.Sync(); err != nil {
+			return nil, err
+		}
+	}
+	if err = o.syncDirOf(f.Name()); err != nil {
+		return nil, err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	o.log.Printf("File %q copied at %q", source, f.Name())
+	return f, nil
+}
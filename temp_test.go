@@ -0,0 +1,60 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTempSpace(t *testing.T) {
+	dir := t.TempDir()
+	ts := NewTempSpace(dir)
+
+	f, err := ts.File("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := f.Stat(); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode().Perm() != 0600 {
+		t.Errorf("got mode %v, want %v", fi.Mode().Perm(), os.FileMode(0600))
+	}
+
+	tmpDir, err := ts.Dir("foo-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(tmpDir, "bar"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = ts.CopytoTemp(FILENAME, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(FILENAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Error("the copy differs")
+	}
+
+	if err = ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("files not removed: %q", files)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+var errNoBirthTime = errors.New("birth time not supported")
+
+// ModTime returns the time of the last modification of the content.
+func (i Info) ModTime() time.Time {
+	return i.fi.ModTime()
+}
+
+// AccessTime returns the time of the last access to the file.
+// In systems without it, it is returned the modification time.
+func (i Info) AccessTime() time.Time {
+	return accessTime(i.fi)
+}
+
+// ChangeTime returns the time of the last change of the file status, like the
+// mode or owner. In systems without it, it is returned the modification time.
+func (i Info) ChangeTime() time.Time {
+	return changeTime(i.fi)
+}
+
+// BirthTime returns the time of creation of the file. It is got at calling it,
+// through the system call statx at Linux, so the filesystem has to support it.
+// For a Info got by NewLinkInfo, it is returned the one of the link.
+func (i Info) BirthTime() (time.Time, error) {
+	return birthTime(i.name, i.IsSymlink())
+}
+
+// * * *
+
+// Touch sets the access and modification times of the named file, or
+// directory, to the current time, creating an empty file if it does not exist.
+// Like the command touch, it is enough to have write permission on the file,
+// without being its owner.
+func Touch(name string) error {
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		if !os.IsExist(err) {
+			return err
+		}
+	} else if err = file.Close(); err != nil {
+		return err
+	}
+
+	return setTimesNow(name)
+}
+
+// SetTimes sets the access and modification times of the named file, with
+// precision of nanoseconds, following symbolic links. A zero time.Time value
+// lefts that time unchanged.
+func SetTimes(name string, atime, mtime time.Time) error {
+	return setTimes(name, atime, mtime, false)
+}
+
+// LSetTimes is like SetTimes but, if the named file is a symbolic link, it
+// sets the times of the link. It is not supported in every system.
+func LSetTimes(name string, atime, mtime time.Time) error {
+	return setTimes(name, atime, mtime, true)
+}
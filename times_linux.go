@@ -0,0 +1,139 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// flags got in: `man 2 statx` and `man 2 utimensat`
+const (
+	atSymlinkNofollow = 0x100
+	statxBtime        = 0x800
+	utimeOmit         = (1 << 30) - 2
+)
+
+// statxTimestamp is the 'struct statx_timestamp' of Linux.
+type statxTimestamp struct {
+	Sec  int64
+	Nsec uint32
+	_    int32
+}
+
+// statxT is the 'struct statx' of Linux.
+type statxT struct {
+	Mask           uint32
+	Blksize        uint32
+	Attributes     uint64
+	Nlink          uint32
+	Uid            uint32
+	Gid            uint32
+	Mode           uint16
+	_              uint16
+	Ino            uint64
+	Size           uint64
+	Blocks         uint64
+	AttributesMask uint64
+	Atime          statxTimestamp
+	Btime          statxTimestamp
+	Ctime          statxTimestamp
+	Mtime          statxTimestamp
+	RdevMajor      uint32
+	RdevMinor      uint32
+	DevMajor       uint32
+	DevMinor       uint32
+	_              [14]uint64
+}
+
+// birthTime returns the time of creation of the named file, through the
+// system call statx. If nofollow is true, it is not followed a symbolic link.
+func birthTime(name string, nofollow bool) (time.Time, error) {
+	if sysStatx == 0 {
+		return time.Time{}, &os.PathError{Op: "statx", Path: name, Err: errNoBirthTime}
+	}
+
+	path, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return time.Time{}, &os.PathError{Op: "statx", Path: name, Err: err}
+	}
+	flags := 0
+	if nofollow {
+		flags = atSymlinkNofollow
+	}
+
+	var stx statxT
+	dirfd := atFDCWD
+	_, _, errno := syscall.Syscall6(sysStatx, uintptr(dirfd), uintptr(unsafe.Pointer(path)),
+		uintptr(flags), statxBtime, uintptr(unsafe.Pointer(&stx)), 0)
+	if errno != 0 {
+		if errno == syscall.ENOSYS {
+			return time.Time{}, &os.PathError{Op: "statx", Path: name, Err: errNoBirthTime}
+		}
+		return time.Time{}, &os.PathError{Op: "statx", Path: name, Err: errno}
+	}
+	if stx.Mask&statxBtime == 0 {
+		return time.Time{}, &os.PathError{Op: "statx", Path: name, Err: errNoBirthTime}
+	}
+
+	return time.Unix(stx.Btime.Sec, int64(stx.Btime.Nsec)), nil
+}
+
+// setTimes sets the access and modification times of the named file, through
+// the system call utimensat. If nofollow is true, it is not followed a
+// symbolic link.
+func setTimes(name string, atime, mtime time.Time, nofollow bool) error {
+	path, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return &os.PathError{Op: "utimensat", Path: name, Err: err}
+	}
+	flags := 0
+	if nofollow {
+		flags = atSymlinkNofollow
+	}
+
+	ts := [2]syscall.Timespec{toTimespec(atime), toTimespec(mtime)}
+	dirfd := atFDCWD
+
+	_, _, errno := syscall.Syscall6(syscall.SYS_UTIMENSAT, uintptr(dirfd),
+		uintptr(unsafe.Pointer(path)), uintptr(unsafe.Pointer(&ts[0])), uintptr(flags), 0, 0)
+	if errno != 0 {
+		return &os.PathError{Op: "utimensat", Path: name, Err: errno}
+	}
+	return nil
+}
+
+// setTimesNow sets the access and modification times of the named file to the
+// current time. The kernel allows it to the users with write permission, unlike
+// when the times are given.
+func setTimesNow(name string) error {
+	path, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return &os.PathError{Op: "utimensat", Path: name, Err: err}
+	}
+	dirfd := atFDCWD
+
+	_, _, errno := syscall.Syscall6(syscall.SYS_UTIMENSAT, uintptr(dirfd),
+		uintptr(unsafe.Pointer(path)), 0, 0, 0, 0)
+	if errno != 0 {
+		return &os.PathError{Op: "utimensat", Path: name, Err: errno}
+	}
+	return nil
+}
+
+// toTimespec returns the time t as Timespec, to not change it if it is zero.
+func toTimespec(t time.Time) syscall.Timespec {
+	if t.IsZero() {
+		return syscall.Timespec{Nsec: utimeOmit}
+	}
+	return syscall.NsecToTimespec(t.UnixNano())
+}
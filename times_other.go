@@ -0,0 +1,51 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !linux
+// +build !linux
+
+package fileutil
+
+import (
+	"os"
+	"time"
+)
+
+// birthTime returns an error since the birth time is not supported in this
+// system.
+func birthTime(name string, nofollow bool) (time.Time, error) {
+	return time.Time{}, &os.PathError{Op: "birthtime", Path: name, Err: errNoBirthTime}
+}
+
+// setTimes sets the access and modification times of the named file.
+// In this system, the symbolic links are always followed, so it is returned an
+// error if nofollow is true.
+func setTimes(name string, atime, mtime time.Time, nofollow bool) error {
+	if nofollow {
+		return &os.PathError{Op: "lsettimes", Path: name, Err: errNotSupported}
+	}
+
+	if atime.IsZero() || mtime.IsZero() {
+		info, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		if atime.IsZero() {
+			atime = accessTime(info)
+		}
+		if mtime.IsZero() {
+			mtime = info.ModTime()
+		}
+	}
+	return os.Chtimes(name, atime, mtime)
+}
+
+// setTimesNow sets the access and modification times of the named file to the
+// current time.
+func setTimesNow(name string) error {
+	now := time.Now()
+	return os.Chtimes(name, now, now)
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestTimes(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "foo")
+	link := filepath.Join(dir, "link")
+
+	before := time.Now().Add(-time.Second)
+	if err := Touch(filename); err != nil {
+		t.Fatal(err)
+	}
+	info, err := NewInfo(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModTime().Before(before) {
+		t.Errorf("Touch: got modification time %v, before of %v", info.ModTime(), before)
+	}
+
+	// The directories are touched too, and a file already there is kept.
+	if err = Touch(dir); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = NewInfo(dir); err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsDir() || info.ModTime().Before(before) {
+		t.Errorf("Touch: got directory %v, modification time %v", info.IsDir(), info.ModTime())
+	}
+	if err = os.WriteFile(filename, []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = Touch(filename); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filename); err != nil || string(data) != "foo" {
+		t.Errorf("Touch: got content %q, %v; want foo", data, err)
+	}
+
+	atime := time.Date(2001, 2, 3, 4, 5, 6, 123456789, time.UTC)
+	mtime := time.Date(2002, 3, 4, 5, 6, 7, 987654321, time.UTC)
+
+	if err = SetTimes(filename, atime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = NewInfo(filename); err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("ModTime: got %v, want %v", info.ModTime(), mtime)
+	}
+	if runtime.GOOS == "linux" && !info.AccessTime().Equal(atime) {
+		t.Errorf("AccessTime: got %v, want %v", info.AccessTime(), atime)
+	}
+	if runtime.GOOS == "linux" && info.ChangeTime().Before(before) {
+		t.Errorf("ChangeTime: got %v, before of %v", info.ChangeTime(), before)
+	}
+
+	// A zero time is not changed.
+	if err = SetTimes(filename, time.Time{}, atime); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = NewInfo(filename); err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(atime) {
+		t.Errorf("ModTime: got %v, want %v", info.ModTime(), atime)
+	}
+	if runtime.GOOS == "linux" && !info.AccessTime().Equal(atime) {
+		t.Errorf("AccessTime: got %v, want %v", info.AccessTime(), atime)
+	}
+
+	if btime, err := info.BirthTime(); err != nil {
+		if !errors.Is(err, errNoBirthTime) {
+			t.Error(err)
+		}
+	} else if btime.Before(before) {
+		t.Errorf("BirthTime: got %v, before of %v", btime, before)
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	if err = os.Symlink("foo", link); err != nil {
+		t.Fatal(err)
+	}
+	if err = LSetTimes(link, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = NewLinkInfo(link); err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("LSetTimes: got %v, want %v", info.ModTime(), mtime)
+	}
+	if info, err = NewInfo(filename); err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(atime) {
+		t.Errorf("LSetTimes: the target changed: got %v, want %v", info.ModTime(), atime)
+	}
+}
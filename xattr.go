@@ -0,0 +1,44 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package fileutil
+
+import "errors"
+
+var errNotSupported = errors.New("not supported in this system")
+
+// Xattr returns the value of the extended attribute attr of the file.
+// For a Info got by NewLinkInfo, it is returned the one of the link.
+func (i Info) Xattr(attr string) ([]byte, error) {
+	if i.IsSymlink() {
+		return LGetXattr(i.name, attr)
+	}
+	return GetXattr(i.name, attr)
+}
+
+// Xattrs returns the extended attributes of the file, with their values, like
+// at Xattr.
+func (i Info) Xattrs() (map[string][]byte, error) {
+	list, get := ListXattr, GetXattr
+	if i.IsSymlink() {
+		list, get = LListXattr, LGetXattr
+	}
+
+	names, err := list(i.name)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string][]byte, len(names))
+	for _, name := range names {
+		value, err := get(i.name, name)
+		if err != nil {
+			return nil, err
+		}
+		attrs[name] = value
+	}
+	return attrs, nil
+}
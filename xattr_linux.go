@@ -0,0 +1,224 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// GetXattr returns the value of the extended attribute attr of the named file.
+func GetXattr(name, attr string) ([]byte, error) { return getXattr(name, attr, false) }
+
+// LGetXattr is like GetXattr but, if the named file is a symbolic link, it
+// returns the attribute of the link.
+func LGetXattr(name, attr string) ([]byte, error) { return getXattr(name, attr, true) }
+
+// SetXattr sets the value of the extended attribute attr of the named file,
+// creating it if it does not exist.
+func SetXattr(name, attr string, value []byte) error {
+	if err := syscall.Setxattr(name, attr, value, 0); err != nil {
+		return &os.PathError{Op: "setxattr", Path: name, Err: err}
+	}
+	return nil
+}
+
+// LSetXattr is like SetXattr but, if the named file is a symbolic link, it
+// sets the attribute of the link.
+func LSetXattr(name, attr string, value []byte) error {
+	if err := lsetxattr(name, attr, value, 0); err != nil {
+		return &os.PathError{Op: "lsetxattr", Path: name, Err: err}
+	}
+	return nil
+}
+
+// ListXattr returns the names of the extended attributes of the named file.
+func ListXattr(name string) ([]string, error) { return listXattr(name, false) }
+
+// LListXattr is like ListXattr but, if the named file is a symbolic link, it
+// returns the attributes of the link.
+func LListXattr(name string) ([]string, error) { return listXattr(name, true) }
+
+// RemoveXattr removes the extended attribute attr of the named file.
+func RemoveXattr(name, attr string) error {
+	if err := syscall.Removexattr(name, attr); err != nil {
+		return &os.PathError{Op: "removexattr", Path: name, Err: err}
+	}
+	return nil
+}
+
+// LRemoveXattr is like RemoveXattr but, if the named file is a symbolic link,
+// it removes the attribute of the link.
+func LRemoveXattr(name, attr string) error {
+	if err := lremovexattr(name, attr); err != nil {
+		return &os.PathError{Op: "lremovexattr", Path: name, Err: err}
+	}
+	return nil
+}
+
+// CopyXattrs copies all extended attributes of the file src to dst, including
+// the POSIX ACLs and the SELinux labels. The attributes which the process has
+// not privileges to set, or which are not supported by the filesystem of dst,
+// are skipped.
+func CopyXattrs(src, dst string) error {
+	o := &options{preserve: PreserveXattr | PreserveACL, log: Log}
+	return o.copyXattrs(src, dst)
+}
+
+// * * *
+
+// listXattr returns the names of the extended attributes of the named file.
+// If nofollow is true, it is not followed a symbolic link.
+func listXattr(name string, nofollow bool) ([]string, error) {
+	op, list := "listxattr", syscall.Listxattr
+	if nofollow {
+		op, list = "llistxattr", llistxattr
+	}
+	var buf []byte
+
+	for {
+		size, err := list(name, nil)
+		if err != nil {
+			return nil, &os.PathError{Op: op, Path: name, Err: err}
+		}
+		if size == 0 {
+			return nil, nil
+		}
+
+		buf = make([]byte, size)
+		size, err = list(name, buf)
+		if err != nil {
+			if err == syscall.ERANGE { // it has grown between both calls
+				continue
+			}
+			return nil, &os.PathError{Op: op, Path: name, Err: err}
+		}
+		buf = buf[:size]
+		break
+	}
+
+	// The names are null-terminated strings.
+	var names []string
+	for start, i := 0, 0; i < len(buf); i++ {
+		if buf[i] == 0 {
+			if i > start {
+				names = append(names, string(buf[start:i]))
+			}
+			start = i + 1
+		}
+	}
+	return names, nil
+}
+
+// getXattr returns the value of the extended attribute attr of the named file.
+// If nofollow is true, it is not followed a symbolic link.
+func getXattr(name, attr string, nofollow bool) ([]byte, error) {
+	op, get := "getxattr", syscall.Getxattr
+	if nofollow {
+		op, get = "lgetxattr", lgetxattr
+	}
+
+	for {
+		size, err := get(name, attr, nil)
+		if err != nil {
+			return nil, &os.PathError{Op: op, Path: name, Err: err}
+		}
+
+		buf := make([]byte, size)
+		size, err = get(name, attr, buf)
+		if err != nil {
+			if err == syscall.ERANGE {
+				continue
+			}
+			return nil, &os.PathError{Op: op, Path: name, Err: err}
+		}
+		// With an empty buffer, it is returned the size of the value, which
+		// could have grown between both calls.
+		if size > len(buf) {
+			continue
+		}
+		return buf[:size], nil
+	}
+}
+
+// The system calls which do not follow symbolic links are not defined at
+// package syscall.
+
+func lgetxattr(path, attr string, dest []byte) (int, error) {
+	p, a, err := xattrPtrs(path, attr)
+	if err != nil {
+		return 0, err
+	}
+	r, _, errno := syscall.Syscall6(syscall.SYS_LGETXATTR, uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(a)), bufPtr(dest), uintptr(len(dest)), 0, 0)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r), nil
+}
+
+func lsetxattr(path, attr string, data []byte, flags int) error {
+	p, a, err := xattrPtrs(path, attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall6(syscall.SYS_LSETXATTR, uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(a)), bufPtr(data), uintptr(len(data)), uintptr(flags), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func llistxattr(path string, dest []byte) (int, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	r, _, errno := syscall.Syscall(syscall.SYS_LLISTXATTR, uintptr(unsafe.Pointer(p)),
+		bufPtr(dest), uintptr(len(dest)))
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r), nil
+}
+
+func lremovexattr(path, attr string) error {
+	p, a, err := xattrPtrs(path, attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_LREMOVEXATTR, uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(a)), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// xattrPtrs returns the path and the name of the attribute as C strings.
+func xattrPtrs(path, attr string) (p, a *byte, err error) {
+	if p, err = syscall.BytePtrFromString(path); err != nil {
+		return nil, nil, err
+	}
+	if a, err = syscall.BytePtrFromString(attr); err != nil {
+		return nil, nil, err
+	}
+	return p, a, nil
+}
+
+// bufPtr returns the pointer to the buffer, or 0 if it is empty.
+func bufPtr(buf []byte) uintptr {
+	if len(buf) == 0 {
+		return 0
+	}
+	return uintptr(unsafe.Pointer(&buf[0]))
+}
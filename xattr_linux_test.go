@@ -0,0 +1,98 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build linux
+// +build linux
+
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestXattr(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	link := filepath.Join(dir, "link")
+
+	for _, name := range []string{src, dst} {
+		if err := os.WriteFile(name, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("src", link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetXattr(src, "user.foo", []byte("bar")); err != nil {
+		if isNotSupported(err) {
+			t.Skip(err)
+		}
+		t.Fatal(err)
+	}
+	if err := SetXattr(link, "user.baz", []byte("qux")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetXattr(dst, "user.empty", nil); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := GetXattr(dst, "user.empty"); err != nil || len(value) != 0 {
+		t.Errorf("GetXattr: got %q, %v; want empty", value, err)
+	}
+	if err := RemoveXattr(dst, "user.empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, err := GetXattr(src, "user.foo"); err != nil || string(value) != "bar" {
+		t.Errorf("GetXattr: got %q, %v; want bar", value, err)
+	}
+	if value, err := GetXattr(link, "user.baz"); err != nil || string(value) != "qux" {
+		t.Errorf("GetXattr through link: got %q, %v; want qux", value, err)
+	}
+	if _, err := LGetXattr(link, "user.foo"); !errors.Is(err, syscall.ENODATA) {
+		t.Errorf("LGetXattr: got %v, want ENODATA", err)
+	}
+	if names, err := LListXattr(link); err != nil || len(names) != 0 {
+		t.Errorf("LListXattr: got %q, %v; want none", names, err)
+	}
+	// The user attributes are not permitted in symbolic links.
+	if err := LSetXattr(link, "user.foo", nil); !isNotPermitted(err) {
+		t.Errorf("LSetXattr: got %v, want EPERM", err)
+	}
+
+	if err := CopyXattrs(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	info, err := NewInfo(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := info.Xattrs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]byte{"user.foo": []byte("bar"), "user.baz": []byte("qux")}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("Xattrs: got %q, want %q", attrs, want)
+	}
+
+	if err = RemoveXattr(dst, "user.foo"); err != nil {
+		t.Fatal(err)
+	}
+	if names, err := ListXattr(dst); err != nil || !reflect.DeepEqual(names, []string{"user.baz"}) {
+		t.Errorf("ListXattr: got %q, %v; want [user.baz]", names, err)
+	}
+	if _, err = info.Xattr("user.foo"); !errors.Is(err, syscall.ENODATA) {
+		t.Errorf("Xattr: got %v, want ENODATA", err)
+	}
+}
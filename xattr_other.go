@@ -0,0 +1,56 @@
+// Copyright 2026 Jonas mg
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !linux
+// +build !linux
+
+package fileutil
+
+import "os"
+
+// GetXattr returns an error since it is not supported in this system.
+func GetXattr(name, attr string) ([]byte, error) {
+	return nil, &os.PathError{Op: "getxattr", Path: name, Err: errNotSupported}
+}
+
+// LGetXattr returns an error since it is not supported in this system.
+func LGetXattr(name, attr string) ([]byte, error) {
+	return nil, &os.PathError{Op: "lgetxattr", Path: name, Err: errNotSupported}
+}
+
+// SetXattr returns an error since it is not supported in this system.
+func SetXattr(name, attr string, value []byte) error {
+	return &os.PathError{Op: "setxattr", Path: name, Err: errNotSupported}
+}
+
+// LSetXattr returns an error since it is not supported in this system.
+func LSetXattr(name, attr string, value []byte) error {
+	return &os.PathError{Op: "lsetxattr", Path: name, Err: errNotSupported}
+}
+
+// ListXattr returns an error since it is not supported in this system.
+func ListXattr(name string) ([]string, error) {
+	return nil, &os.PathError{Op: "listxattr", Path: name, Err: errNotSupported}
+}
+
+// LListXattr returns an error since it is not supported in this system.
+func LListXattr(name string) ([]string, error) {
+	return nil, &os.PathError{Op: "llistxattr", Path: name, Err: errNotSupported}
+}
+
+// RemoveXattr returns an error since it is not supported in this system.
+func RemoveXattr(name, attr string) error {
+	return &os.PathError{Op: "removexattr", Path: name, Err: errNotSupported}
+}
+
+// LRemoveXattr returns an error since it is not supported in this system.
+func LRemoveXattr(name, attr string) error {
+	return &os.PathError{Op: "lremovexattr", Path: name, Err: errNotSupported}
+}
+
+// CopyXattrs does nothing since the extended attributes are not supported in
+// this system.
+func CopyXattrs(src, dst string) error { return nil }